@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"charm.land/huh/v2"
+	"dario.cat/mergo"
+
+	"github.com/kickr-dev/engine/pkg/files"
+	"github.com/kickr-dev/engine/pkg/internal/values"
+)
+
+// ErrInvalidAnswers is returned by Initialize in non-interactive mode
+// when some answers are missing or don't pass their field validation.
+var ErrInvalidAnswers = errors.New("invalid answers")
+
+// AnswersSource is the signature function for functions providing Initialize answers
+// (see WithAnswers, AnswersMap, AnswersFile and AnswersEnv).
+//
+// Nested answers are represented with nested maps.
+type AnswersSource func() (map[string]any, error)
+
+// AnswersMap returns an AnswersSource providing input answers.
+func AnswersMap(answers map[string]any) AnswersSource {
+	return func() (map[string]any, error) {
+		return maps.Clone(answers), nil
+	}
+}
+
+// AnswersFile returns an AnswersSource reading answers from input src file,
+// in YAML (.yml, .yaml), JSON (.json) or TOML (.toml) format according to its extension.
+func AnswersFile(src string) AnswersSource {
+	return func() (map[string]any, error) {
+		format, err := answersFormatFor(src)
+		if err != nil {
+			return nil, err
+		}
+
+		answers := map[string]any{}
+		if err := format.read(os.DirFS(filepath.Dir(src)), filepath.Base(src), &answers); err != nil {
+			return nil, fmt.Errorf("read answers file: %w", err)
+		}
+		return answers, nil
+	}
+}
+
+// answersFormat represents the read and write functions of an answers file format.
+type answersFormat struct {
+	read  func(fsys fs.FS, src string, out any) error
+	write func(out string, data any) error
+}
+
+// answersFormatFor returns the answers file format associated to input src extension.
+func answersFormatFor(src string) (answersFormat, error) {
+	switch strings.ToLower(filepath.Ext(src)) {
+	case ".json":
+		return answersFormat{read: files.ReadJSON, write: files.WriteJSON}, nil
+	case ".toml":
+		return answersFormat{read: files.ReadTOML, write: files.WriteTOML}, nil
+	case ".yml", ".yaml":
+		write := func(out string, data any) error { return files.WriteYAML(out, data) }
+		return answersFormat{read: files.ReadYAML, write: write}, nil
+	default:
+		return answersFormat{}, fmt.Errorf("unsupported answers file format '%s'", filepath.Ext(src))
+	}
+}
+
+// AnswersEnv returns an AnswersSource reading answers from environment variables starting with input prefix.
+//
+// The prefix is removed from variables names, the remaining name is lowercased
+// and double underscores are used as nesting separators, e.g. with "KICKR_" prefix,
+// KICKR_MAINTAINER__NAME=jane gives the answer {"maintainer": {"name": "jane"}}.
+func AnswersEnv(prefix string) AnswersSource {
+	return func() (map[string]any, error) {
+		return values.FromEnv(prefix), nil
+	}
+}
+
+// readAnswers reads and merges all input answers sources, in order (later sources override previous ones).
+func readAnswers(sources ...AnswersSource) (map[string]any, error) {
+	answers := map[string]any{}
+	for _, source := range sources {
+		current, err := source()
+		if err != nil {
+			return nil, err
+		}
+		if err := mergo.Merge(&answers, current, mergo.WithOverride); err != nil {
+			return nil, fmt.Errorf("merge answers: %w", err)
+		}
+	}
+	return answers, nil
+}
+
+// decodeAnswers decodes input answers into config.
+//
+// Answers keys are matched against config fields JSON names (their "json" tag or their name)
+// case insensitively and ignoring underscores and dashes, e.g. "maintainer_name" matches MaintainerName.
+// Scalar values are converted when needed (e.g. "true" into a bool), since environment variables are strings.
+func decodeAnswers(answers map[string]any, config any) error {
+	if err := values.Decode(answers, config, "json"); err != nil {
+		return fmt.Errorf("decode answers: %w", err)
+	}
+	return nil
+}
+
+// validateForm walks through all fields of input groups (skipping hidden ones) without any user interaction,
+// running each field validation against its current value.
+//
+// Each group visibility is evaluated against current values (as an interactive form would)
+// and all fields errors are returned wrapped with ErrInvalidAnswers.
+func validateForm(groups ...*huh.Group) error {
+	var errs []error
+	position := 0
+	for _, group := range groups {
+		form := headlessForm(group)
+		if form.State != huh.StateNormal {
+			continue // hidden group
+		}
+		for {
+			field := form.GetFocusedField()
+			_ = field.Blur() // run validation
+			if err := field.Error(); err != nil {
+				name := field.GetKey()
+				if name == "" {
+					name = fmt.Sprintf("#%d", position)
+				}
+				errs = append(errs, fmt.Errorf("field '%s': %w", name, err))
+			}
+			position++
+
+			if _ = form.NextField(); form.GetFocusedField() == field {
+				break // last field of the group
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidAnswers, errors.Join(errs...))
+	}
+	return nil
+}
+
+// headlessForm returns an initialized form (without any program) made of input groups,
+// focused on the first field of the first group not hidden.
+//
+// The form state is huh.StateCompleted when all groups are hidden.
+func headlessForm(groups ...*huh.Group) *huh.Form {
+	// a visible group is needed first, since huh only skips hidden groups when moving to the next one
+	form := huh.NewForm(append([]*huh.Group{huh.NewGroup(huh.NewNote())}, groups...)...)
+	_ = form.Init() // commands aren't run since there's no program
+	_ = form.NextGroup()
+	return form
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+// Checksums is the store of generated files checksums, used with PolicyHash
+// to know whether a generated file was modified since the engine last wrote it.
+//
+// Files paths are stored relative to the checksums file directory, with slashes as separators,
+// so that the checksums file can be committed alongside the generated repository.
+//
+// A nil *Checksums is valid and stores nothing.
+// It's safe for concurrent use, since Generate runs generators concurrently.
+type Checksums struct {
+	mu   sync.RWMutex
+	dir  string
+	src  string
+	sums map[string]string
+}
+
+// ReadChecksums reads the checksums file (JSON format) at src and returns its representation.
+//
+// A missing file isn't an error, an empty store is returned in that case
+// (Checksums.Write will create it).
+func ReadChecksums(src string) (*Checksums, error) {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return nil, fmt.Errorf("absolute path: %w", err)
+	}
+
+	sums := map[string]string{}
+	if err := files.ReadJSON(os.DirFS(filepath.Dir(abs)), filepath.Base(abs), &sums); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read checksums: %w", err)
+	}
+	return &Checksums{dir: filepath.Dir(abs), src: abs, sums: sums}, nil
+}
+
+// Sum returns the checksum stored for input out file.
+func (c *Checksums) Sum(out string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	key, ok := c.key(out)
+	if !ok {
+		return "", false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sum, ok := c.sums[key]
+	return sum, ok
+}
+
+// Set stores the checksum of content for input out file.
+func (c *Checksums) Set(out string, content []byte) {
+	if c == nil {
+		return
+	}
+	key, ok := c.key(out)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sums[key] = checksum(content)
+}
+
+// Delete removes the checksum stored for input out file.
+func (c *Checksums) Delete(out string) {
+	if c == nil {
+		return
+	}
+	key, ok := c.key(out)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sums, key)
+}
+
+// Write writes the checksums back into the file they were read from.
+//
+// It should be called once Generate is done.
+func (c *Checksums) Write() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return files.WriteJSON(c.src, c.sums)
+}
+
+// key returns the storage key of input out file, i.e. its path relative to checksums file directory.
+func (c *Checksums) key(out string) (string, bool) {
+	abs, err := filepath.Abs(out)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(c.dir, abs)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// checksum returns the hexadecimal SHA-256 checksum of content.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package engine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+func TestReadChecksums(t *testing.T) {
+	t.Run("error_read", func(t *testing.T) {
+		// Arrange
+		src := filepath.Join(t.TempDir(), "checksums.json")
+		require.NoError(t, os.WriteFile(src, []byte("invalid"), files.RwRR))
+
+		// Act
+		_, err := engine.ReadChecksums(src)
+
+		// Assert
+		assert.ErrorContains(t, err, "read checksums")
+	})
+
+	t.Run("success_missing", func(t *testing.T) {
+		// Arrange
+		src := filepath.Join(t.TempDir(), "checksums.json")
+
+		// Act
+		checksums, err := engine.ReadChecksums(src)
+
+		// Assert
+		require.NoError(t, err)
+		_, ok := checksums.Sum(filepath.Join(filepath.Dir(src), "file.txt"))
+		assert.False(t, ok)
+	})
+
+	t.Run("success_write_read", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		src := filepath.Join(destdir, "checksums.json")
+		out := filepath.Join(destdir, "dir", "file.txt")
+
+		checksums, err := engine.ReadChecksums(src)
+		require.NoError(t, err)
+		checksums.Set(out, []byte("content"))
+		checksums.Set(filepath.Join(destdir, "removed.txt"), []byte("content"))
+		checksums.Delete(filepath.Join(destdir, "removed.txt"))
+		require.NoError(t, checksums.Write())
+
+		// Act
+		checksums, err = engine.ReadChecksums(src)
+
+		// Assert
+		require.NoError(t, err)
+		sum, ok := checksums.Sum(out)
+		assert.True(t, ok)
+		assert.Equal(t, "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73", sum)
+		_, ok = checksums.Sum(filepath.Join(destdir, "removed.txt"))
+		assert.False(t, ok)
+
+		content, err := os.ReadFile(src)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"dir/file.txt": "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"}`, string(content))
+	})
+
+	t.Run("success_nil", func(t *testing.T) {
+		// Arrange
+		var checksums *engine.Checksums
+
+		// Act
+		checksums.Set("file.txt", []byte("content"))
+		_, ok := checksums.Sum("file.txt")
+
+		// Assert
+		assert.False(t, ok)
+		assert.NoError(t, checksums.Write())
+	})
+}
@@ -0,0 +1,45 @@
+/*
+Package config loads and writes a project configuration file (e.g. ".kickr.yml") into a typed configuration.
+
+The configuration file is discovered across its YAML, TOML and JSON variants,
+validated against a JSON schema and decoded, errors being reported with their position in the file:
+
+	cfg, _, err := config.Load[Config](os.DirFS(destdir), ".kickr", config.WithSchema(schemas, "kickr.schema.json"))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		// handle err
+	}
+
+	// ...
+
+	if err := config.Write(filepath.Join(destdir, ".kickr.yml"), cfg); err != nil {
+		// handle err
+	}
+
+Older configuration files are migrated with WithMigrations, each Migration operating on the raw document
+before its validation and decoding:
+
+	cfg, _, err := config.Load[Config](os.DirFS(destdir), ".kickr",
+		config.WithMigrations(2,
+			config.Migration{From: 0, Migrate: renameProject},
+			config.Migration{From: 1, Migrate: moveMaintainer},
+		),
+		config.WithRewrite(destdir))
+	if errors.Is(err, config.ErrUnsupportedVersion) {
+		// the configuration was written by a newer version of the tool, generation must not run
+	}
+
+Organization defaults, team values, repository configuration and environment variables can be layered with LoadLayers
+(e.g. before giving the configuration to engine.Generate), keeping track of the layer each value comes from:
+
+	cfg, origins, err := config.LoadLayers[Config](
+		config.FileLayer("organization", orgfs, "defaults"),
+		config.FileLayer("team", teamfs, "team"),
+		config.FileLayer("repository", os.DirFS(destdir), ".kickr"),
+		config.EnvLayer("environment", "KICKR_"),
+	)
+	if err != nil {
+		// handle err
+	}
+	layer, _ := origins.Of("/maintainer/name") // e.g. "team"
+*/
+package config
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// extensions is the ordered list of configuration file extensions searched by Load.
+var extensions = []string{".yaml", ".yml", ".toml", ".json"}
+
+// format represents a configuration file format, with its (un)marshalling functions
+// and the way to find a position in a file content.
+type format struct {
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(content []byte, out any) error
+
+	// errorPosition returns the line and column of an unmarshal error (zeros when unknown).
+	errorPosition func(content []byte, err error) (int, int)
+
+	// locate returns the line and column of the value at input path (JSON pointer segments) in content,
+	// doc being the unmarshalled content (zeros when unknown).
+	locate func(content []byte, doc any, segments []string) (int, int)
+}
+
+// formats is the map of supported configuration formats by file extension.
+var formats = map[string]format{
+	".json": {
+		marshal: func(v any) ([]byte, error) {
+			content, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			return append(content, '\n'), nil
+		},
+		unmarshal:     json.Unmarshal,
+		errorPosition: jsonErrorPosition,
+		locate:        yamlLocate, // JSON is a subset of YAML
+	},
+	".toml": {
+		marshal:       toml.Marshal,
+		unmarshal:     toml.Unmarshal,
+		errorPosition: tomlErrorPosition,
+		locate:        tomlLocate,
+	},
+	".yaml": {
+		marshal:       yaml.Marshal,
+		unmarshal:     yaml.Unmarshal,
+		errorPosition: yamlErrorPosition,
+		locate:        yamlLocate,
+	},
+	".yml": {
+		marshal:       yaml.Marshal,
+		unmarshal:     yaml.Unmarshal,
+		errorPosition: yamlErrorPosition,
+		locate:        yamlLocate,
+	},
+}
+
+// formatOf returns the format of input file, based on its extension.
+func formatOf(name string) (format, bool) {
+	f, ok := formats[strings.ToLower(path.Ext(name))]
+	return f, ok
+}
+
+// jsonErrorPosition returns the position of a JSON syntax or type error.
+func jsonErrorPosition(content []byte, err error) (int, int) {
+	if se := (&json.SyntaxError{}); errors.As(err, &se) {
+		return offsetPosition(content, se.Offset-1) // offset is after the invalid character
+	}
+	if te := (&json.UnmarshalTypeError{}); errors.As(err, &te) {
+		return offsetPosition(content, te.Offset)
+	}
+	return 0, 0
+}
+
+// offsetPosition converts a bytes offset in content into a line and a column.
+func offsetPosition(content []byte, offset int64) (int, int) {
+	offset = min(max(offset, 0), int64(len(content)))
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// tomlErrorPosition returns the position of a TOML decoding error.
+func tomlErrorPosition(_ []byte, err error) (int, int) {
+	if de := (&toml.DecodeError{}); errors.As(err, &de) {
+		return de.Position()
+	}
+	return 0, 0
+}
+
+// tomlLocate returns the position of the key closest to input path segments in TOML content.
+//
+// Values inside inline arrays or inline tables are located at their parent key.
+func tomlLocate(content []byte, _ any, segments []string) (int, int) {
+	positions := map[string]unstable.Position{}
+	arrays := map[string]int{}
+
+	p := unstable.Parser{}
+	p.Reset(content)
+
+	var table []string
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = nil
+			for key := expr.Key(); key.Next(); {
+				table = append(table, string(key.Node().Data))
+				pointer := path.Join(table...)
+				if _, ok := positions[pointer]; !ok {
+					positions[pointer] = p.Shape(key.Node().Raw).Start
+				}
+			}
+			if expr.Kind == unstable.ArrayTable {
+				pointer := path.Join(table...)
+				table = append(table, strconv.Itoa(arrays[pointer]))
+				arrays[pointer]++
+				positions[path.Join(table...)] = positions[pointer]
+			}
+		case unstable.KeyValue:
+			keys := table
+			for key := expr.Key(); key.Next(); {
+				keys = append(keys, string(key.Node().Data))
+				pointer := path.Join(keys...)
+				if _, ok := positions[pointer]; !ok {
+					positions[pointer] = p.Shape(key.Node().Raw).Start
+				}
+			}
+		default:
+		}
+	}
+
+	for i := len(segments); i > 0; i-- {
+		if position, ok := positions[path.Join(segments[:i]...)]; ok {
+			return position.Line, position.Column
+		}
+	}
+	return 0, 0
+}
+
+// yamlErrorPosition returns the position of a YAML syntax or type error.
+func yamlErrorPosition(_ []byte, err error) (int, int) {
+	var ye yaml.Error
+	if errors.As(err, &ye) && ye.GetToken() != nil {
+		return ye.GetToken().Position.Line, ye.GetToken().Position.Column
+	}
+	return 0, 0
+}
+
+// yamlLocate returns the position of the value closest to input path segments in YAML (or JSON) content.
+func yamlLocate(content []byte, doc any, segments []string) (int, int) {
+	file, err := parser.ParseBytes(content, 0)
+	if err != nil {
+		return 0, 0
+	}
+
+	for i := len(segments); i >= 0; i-- {
+		builder := (&yaml.PathBuilder{}).Root()
+		current := doc
+		for _, segment := range segments[:i] {
+			if values, ok := current.([]any); ok {
+				index, _ := strconv.Atoi(segment)
+				builder = builder.Index(uint(max(index, 0))) //nolint:gosec // index is positive
+				if index >= 0 && index < len(values) {
+					current = values[index]
+				}
+				continue
+			}
+			builder = builder.Child(segment)
+			if values, ok := current.(map[string]any); ok {
+				current = values[segment]
+			}
+		}
+
+		node, err := builder.Build().FilterFile(file)
+		if err != nil || node == nil || node.GetToken() == nil {
+			continue
+		}
+		return node.GetToken().Position.Line, node.GetToken().Position.Column
+	}
+	return 0, 0
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/kickr-dev/engine/pkg/internal/values"
+)
+
+// Layer represents a named configuration layer (e.g. organization defaults, team values, repository configuration)
+// for LoadLayers.
+type Layer struct {
+	// Name is the layer name, used in Origins to explain where a value comes from.
+	Name string
+
+	// Read returns the layer raw values, a nil map meaning the layer is skipped (e.g. missing file).
+	Read func() (map[string]any, error)
+}
+
+// FileLayer returns the Layer reading the configuration file name in fsys, just like Load
+// (file discovery, migrations, schema validation, etc.).
+//
+// The layer is skipped when the configuration file doesn't exist.
+func FileLayer(name string, fsys fs.FS, file string, opts ...LoadOption) Layer {
+	return Layer{
+		Name: name,
+		Read: func() (map[string]any, error) {
+			lo := newLoadOptions(opts...)
+			d, err := lo.read(fsys, file)
+			if errors.Is(err, ErrNotFound) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			values, ok := d.doc.(map[string]any)
+			if !ok && d.doc != nil {
+				return nil, &Error{File: d.src, Err: errors.New("configuration must be an object to be layered")}
+			}
+			if err := lo.write(d); err != nil {
+				return nil, err
+			}
+			return values, nil
+		},
+	}
+}
+
+// MapLayer returns the Layer with input values.
+func MapLayer(name string, values map[string]any) Layer {
+	return Layer{
+		Name: name,
+		Read: func() (map[string]any, error) { return values, nil },
+	}
+}
+
+// EnvLayer returns the Layer reading all environment variables starting with prefix,
+// the prefix is removed, the rest is lowercased and "__" separates nested keys
+// (e.g. "KICKR_MAINTAINER__NAME" with prefix "KICKR_" gives "maintainer" > "name").
+func EnvLayer(name, prefix string) Layer {
+	return Layer{
+		Name: name,
+		Read: func() (map[string]any, error) { return values.FromEnv(prefix), nil },
+	}
+}
+
+// Origins maps each value of a layered configuration, by JSON pointer (e.g. "/maintainer/name"),
+// to the name of the layer it came from.
+type Origins map[string]string
+
+// Of returns the name of the layer input pointer value came from (e.g. "/maintainer/name"),
+// or the one of its closest parent when the value is part of a bigger value (e.g. an item of a list).
+//
+// Pointers are matched just like keys are (see LoadLayers), it returns false when the value isn't set by any layer.
+func (o Origins) Of(pointer string) (string, bool) {
+	normalized := normalizePointer(pointer)
+	for {
+		for candidate, layer := range o {
+			if normalizePointer(candidate) == normalized {
+				return layer, true
+			}
+		}
+		if normalized == "/" || normalized == "" {
+			return "", false
+		}
+		normalized = path.Dir(normalized)
+	}
+}
+
+// LoadLayers reads all input layers in order and merges them into a new T,
+// later layers overriding values of previous ones (maps are merged, any other value is replaced).
+//
+// It returns the decoded configuration and the Origins of each value, to explain why a setting has a given value.
+//
+// Keys are matched case insensitively and ignoring underscores and dashes
+// (e.g. "maintainer_name" from an environment variable matches "maintainerName" from a file)
+// and are decoded against T fields names read from their "yaml", "toml" or "json" tag (the first one present)
+// or their name, for fields to be filled by LoadLayers whatever the tag Load reads them from.
+// Scalar values are converted when needed (e.g. "true" into a bool), since environment variables are strings.
+//
+// Example:
+//
+//	cfg, origins, err := config.LoadLayers[Config](
+//		config.FileLayer("organization", orgfs, "defaults"),
+//		config.FileLayer("team", teamfs, "team"),
+//		config.FileLayer("repository", os.DirFS(destdir), ".kickr"),
+//		config.EnvLayer("environment", "KICKR_"),
+//	)
+//	if err != nil {
+//		// handle err
+//	}
+//	layer, _ := origins.Of("/maintainer/name") // e.g. "team"
+func LoadLayers[T any](layers ...Layer) (T, Origins, error) {
+	var config T
+
+	merged := map[string]any{}
+	origins := Origins{}
+	for _, layer := range layers {
+		if layer.Read == nil {
+			continue
+		}
+		current, err := layer.Read()
+		if err != nil {
+			return config, nil, fmt.Errorf("read layer '%s': %w", layer.Name, err)
+		}
+		mergeLayer(merged, current, "", layer.Name, origins)
+	}
+
+	if err := values.Decode(merged, &config, "yaml,toml,json"); err != nil {
+		return config, nil, fmt.Errorf("decode layers: %w", err)
+	}
+	return config, origins, nil
+}
+
+// mergeLayer merges src into dst (src values overriding dst ones except for maps which are merged),
+// recording in origins the layer of each merged value.
+func mergeLayer(dst, src map[string]any, pointer, layer string, origins Origins) {
+	for key, value := range src {
+		// an existing key is kept as is in case it's written differently in src
+		for existing := range dst {
+			if values.NormalizeKey(existing) == values.NormalizeKey(key) {
+				key = existing
+				break
+			}
+		}
+		current := pointer + "/" + key
+
+		srcMap, srcIsMap := value.(map[string]any)
+		if dstMap, ok := dst[key].(map[string]any); ok && srcIsMap {
+			mergeLayer(dstMap, srcMap, current, layer, origins)
+			continue
+		}
+
+		// overridden value is removed from origins, including all its children in case it was a map
+		for p := range origins {
+			if p == current || strings.HasPrefix(p, current+"/") {
+				delete(origins, p)
+			}
+		}
+		if srcIsMap && len(srcMap) > 0 {
+			merged := map[string]any{}
+			mergeLayer(merged, srcMap, current, layer, origins)
+			dst[key] = merged
+			continue
+		}
+		dst[key] = value
+		origins[current] = layer
+	}
+}
+
+// normalizePointer returns the comparable version of a JSON pointer, each of its keys being normalized.
+func normalizePointer(pointer string) string {
+	segments := strings.Split(pointer, "/")
+	for i, segment := range segments {
+		segments[i] = values.NormalizeKey(segment)
+	}
+	return path.Clean("/" + strings.Join(segments, "/"))
+}
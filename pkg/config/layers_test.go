@@ -0,0 +1,138 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kickr-dev/engine/pkg/config"
+)
+
+type layeredconfig struct {
+	Name           string   `json:"name"`
+	Labels         []string `json:"labels"`
+	MaintainerName string   `json:"maintainerName"`
+	CI             struct {
+		Enabled bool   `json:"enabled"`
+		Runner  string `json:"runner"`
+	} `json:"ci"`
+	Port int `json:"port"`
+}
+
+func TestLoadLayers(t *testing.T) {
+	t.Run("error_read", func(t *testing.T) {
+		// Arrange
+		layer := config.Layer{Name: "broken", Read: func() (map[string]any, error) { return nil, errors.New("an error") }}
+
+		// Act
+		_, _, err := config.LoadLayers[layeredconfig](layer)
+
+		// Assert
+		assert.EqualError(t, err, "read layer 'broken': an error")
+	})
+
+	t.Run("error_file", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{"defaults.yml": {Data: []byte("name: [")}}
+
+		// Act
+		_, _, err := config.LoadLayers[layeredconfig](config.FileLayer("organization", fsys, "defaults"))
+
+		// Assert
+		var cerr *config.Error
+		assert.ErrorAs(t, err, &cerr)
+		assert.ErrorContains(t, err, "read layer 'organization': defaults.yml:1:")
+	})
+
+	t.Run("error_decode", func(t *testing.T) {
+		// Act
+		_, _, err := config.LoadLayers[layeredconfig](config.MapLayer("map", map[string]any{"port": "port"}))
+
+		// Assert
+		assert.ErrorContains(t, err, "decode layers")
+	})
+
+	t.Run("success_format_tags", func(t *testing.T) {
+		// Arrange
+		type tagged struct {
+			Owner  string `yaml:"owner"`
+			Team   string `toml:"team_name"`
+			Runner string `json:"runner" yaml:"ci_runner"`
+		}
+		fsys := fstest.MapFS{".kickr.yml": {Data: []byte("owner: jane\nci_runner: shared\n")}}
+		loaded, _, err := config.Load[tagged](fsys, ".kickr")
+		require.NoError(t, err)
+
+		// Act
+		actual, _, err := config.LoadLayers[tagged](
+			config.FileLayer("repository", fsys, ".kickr"),
+			config.MapLayer("team", map[string]any{"team_name": "core"}))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, tagged{Owner: "jane", Runner: "shared"}, loaded)
+		assert.Equal(t, tagged{Owner: "jane", Team: "core", Runner: "shared"}, actual)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		organization := fstest.MapFS{"defaults.yml": {Data: []byte("name: default\nlabels: [a, b]\nci:\n  enabled: true\n  runner: shared\nport: 80\n")}}
+		team := fstest.MapFS{"team.toml": {Data: []byte("maintainer_name = 'team'\nlabels = ['c']\n\n[ci]\nrunner = 'team'\n")}}
+		repository := fstest.MapFS{".kickr.json": {Data: []byte(`{"name": "engine", "maintainerName": "jane"}`)}}
+		t.Setenv("KICKR_PORT", "8080")
+		t.Setenv("KICKR_CI__ENABLED", "false")
+
+		expected := layeredconfig{Name: "engine", Labels: []string{"c"}, MaintainerName: "jane", Port: 8080}
+		expected.CI.Runner = "team"
+
+		// Act
+		actual, origins, err := config.LoadLayers[layeredconfig](
+			config.FileLayer("organization", organization, "defaults"),
+			config.FileLayer("team", team, "team"),
+			config.FileLayer("missing", fstest.MapFS{}, "missing"),
+			config.FileLayer("repository", repository, ".kickr"),
+			config.EnvLayer("environment", "KICKR_"),
+		)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, config.Origins{
+			"/name":            "repository",
+			"/labels":          "team",
+			"/ci/enabled":      "environment",
+			"/ci/runner":       "team",
+			"/port":            "environment",
+			"/maintainer_name": "repository",
+		}, origins)
+	})
+}
+
+func TestOriginsOf(t *testing.T) {
+	origins := config.Origins{"/maintainer_name": "repository", "/labels": "team", "/ci/runner": "team"}
+
+	cases := []struct {
+		pointer  string
+		expected string
+		ok       bool
+	}{
+		{pointer: "/maintainerName", expected: "repository", ok: true},
+		{pointer: "/labels/0", expected: "team", ok: true},
+		{pointer: "/CI/Runner", expected: "team", ok: true},
+		{pointer: "/ci"},
+		{pointer: "/port"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.pointer, func(t *testing.T) {
+			// Act
+			layer, ok := origins.Of(tc.pointer)
+
+			// Assert
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, layer)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+// ErrNotFound is returned by Load when no configuration file exists with any of the supported extensions.
+var ErrNotFound = errors.New("configuration file not found")
+
+// Error represents an error located in a configuration file.
+type Error struct {
+	// File is the path of the configuration file.
+	File string
+
+	// Line and Column are the position of the error in File, both starting at 1 (zeros when unknown).
+	Line   int
+	Column int
+
+	// Property is the JSON pointer of the invalid value (e.g. "/maintainer/name"), only set for schema validation errors.
+	Property string
+
+	Err error
+}
+
+var _ error = (*Error)(nil) // ensure interface is implemented
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.File)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", e.Line, e.Column)
+	}
+	b.WriteString(": ")
+	if e.Property != "" {
+		fmt.Fprintf(&b, "at '%s': ", e.Property)
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// LoadOption represents an option for Load.
+type LoadOption func(loadOptions) loadOptions
+
+type loadOptions struct {
+	migrations []Migration
+	rewrite    string
+	schema     string
+	schemaFS   fs.FS
+	version    int
+	versionKey string
+}
+
+// WithSchema validates the configuration file against the JSON schema src read from fsys
+// (the schema can be written in JSON, YAML or TOML, according to its extension).
+func WithSchema(fsys fs.FS, src string) LoadOption {
+	return func(lo loadOptions) loadOptions {
+		lo.schemaFS = fsys
+		lo.schema = src
+		return lo
+	}
+}
+
+// Find returns the path of the configuration file name in fsys.
+//
+// When name has one of the supported extensions (".yaml", ".yml", ".toml" or ".json") it's used as is,
+// otherwise each extension is tried in this order and the first existing file is returned.
+//
+// Input name must be relative to fsys (see io/fs.FS and io/fs.ValidPath), not absolute.
+func Find(fsys fs.FS, name string) (string, error) {
+	candidates := []string{name}
+	if _, ok := formatOf(name); !ok {
+		candidates = make([]string, 0, len(extensions))
+		for _, ext := range extensions {
+			candidates = append(candidates, name+ext)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := fs.Stat(fsys, candidate); err == nil {
+			return candidate, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("stat: %w", err)
+		}
+	}
+	return "", fmt.Errorf("%w: '%s'", ErrNotFound, name)
+}
+
+// Load finds the configuration file name in fsys (see Find), migrates it (see WithMigrations),
+// validates it against the schema given with WithSchema (if any) and decodes it into a new T.
+//
+// It returns the decoded configuration and the path of the configuration file.
+// Syntax, decoding and schema validation errors are returned as (joined) Error with their position in the file
+// (unknown once the file is migrated).
+//
+// The configuration is decoded with the file format specific tags ("yaml", "toml" or "json").
+//
+// Example:
+//
+//	config, src, err := config.Load[Config](os.DirFS(destdir), ".kickr", config.WithSchema(schemas, "kickr.schema.json"))
+//	if errors.Is(err, config.ErrNotFound) {
+//		// use default configuration
+//	}
+func Load[T any](fsys fs.FS, name string, opts ...LoadOption) (T, string, error) {
+	var config T
+
+	lo := newLoadOptions(opts...)
+	d, err := lo.read(fsys, name)
+	if err != nil {
+		return config, d.src, err
+	}
+
+	if err := d.f.unmarshal(d.content, &config); err != nil {
+		return config, d.src, newError(d.src, d.content, d.f, err)
+	}
+	if err := lo.write(d); err != nil {
+		return config, d.src, err
+	}
+	return config, d.src, nil
+}
+
+// newLoadOptions returns the loadOptions with defaults and input options applied.
+func newLoadOptions(opts ...LoadOption) loadOptions {
+	lo := loadOptions{versionKey: DefaultVersionKey}
+	for _, opt := range opts {
+		if opt != nil {
+			lo = opt(lo)
+		}
+	}
+	return lo
+}
+
+// document represents a configuration file read (and migrated) by loadOptions read.
+type document struct {
+	content  []byte
+	doc      any
+	f        format
+	migrated bool
+	src      string
+}
+
+// read finds the configuration file name in fsys, migrates it and validates it against the schema (if any).
+func (lo loadOptions) read(fsys fs.FS, name string) (document, error) {
+	src, err := Find(fsys, name)
+	if err != nil {
+		return document{}, err
+	}
+	d := document{src: src}
+	d.f, _ = formatOf(src) // Find only returns files with supported extensions
+
+	if d.content, err = fs.ReadFile(fsys, src); err != nil {
+		return d, fmt.Errorf("read file: %w", err)
+	}
+	if err := d.f.unmarshal(d.content, &d.doc); err != nil {
+		return d, newError(src, d.content, d.f, err)
+	}
+
+	if d.migrated, err = lo.migrate(src, d.content, d.f, d.doc); err != nil {
+		return d, err
+	}
+	if d.migrated {
+		if d.content, err = d.f.marshal(d.doc); err != nil {
+			return d, fmt.Errorf("marshal migrated configuration: %w", err)
+		}
+		// positions in the migrated content don't match the file ones
+		d.f.errorPosition = func([]byte, error) (int, int) { return 0, 0 }
+		d.f.locate = func([]byte, any, []string) (int, int) { return 0, 0 }
+	}
+
+	if lo.schemaFS != nil {
+		if err := validate(src, d.content, d.f, d.doc, lo); err != nil {
+			return d, err
+		}
+	}
+	return d, nil
+}
+
+// write rewrites input document when it was migrated and WithRewrite was given.
+func (lo loadOptions) write(d document) error {
+	if !d.migrated || lo.rewrite == "" {
+		return nil
+	}
+	if err := Write(filepath.Join(lo.rewrite, filepath.FromSlash(d.src)), d.doc); err != nil {
+		return fmt.Errorf("rewrite migrated configuration: %w", err)
+	}
+	return nil
+}
+
+// newError returns the Error located at the position of input unmarshal error.
+func newError(src string, content []byte, f format, err error) error {
+	line, column := f.errorPosition(content, err)
+	return &Error{File: src, Line: line, Column: column, Err: err}
+}
+
+// validate validates input unmarshalled doc against the schema from options,
+// returning the validation errors located in content.
+func validate(src string, content []byte, f format, doc any, lo loadOptions) error {
+	schemaFormat, ok := formatOf(lo.schema)
+	if !ok {
+		return fmt.Errorf("unsupported schema format '%s'", path.Ext(lo.schema))
+	}
+	readSchema := func(out any) error {
+		schema, err := fs.ReadFile(lo.schemaFS, lo.schema)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+		return schemaFormat.unmarshal(schema, out)
+	}
+
+	// the document is normalized as JSON since YAML or TOML specific types (e.g. TOML dates) aren't known by JSON schemas
+	readFile := func(out any) error {
+		normalized, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+		return json.Unmarshal(normalized, out)
+	}
+
+	err := files.Validate(readSchema, readFile)
+	if err == nil {
+		return nil
+	}
+
+	verrs := validationErrors(err)
+	if len(verrs) == 0 {
+		return err
+	}
+	errs := make([]error, 0, len(verrs))
+	for _, verr := range verrs {
+		var segments []string
+		if verr.Property != "/" {
+			segments = strings.Split(strings.TrimPrefix(verr.Property, "/"), "/")
+		}
+		line, column := f.locate(content, doc, segments)
+		errs = append(errs, &Error{
+			File:     src,
+			Line:     line,
+			Column:   column,
+			Property: verr.Property,
+			Err:      errors.New(verr.Message),
+		})
+	}
+	return fmt.Errorf("validate schema:\n%w", errors.Join(errs...))
+}
+
+// validationErrors returns all files.ValidationError wrapped in input error tree.
+func validationErrors(err error) []*files.ValidationError {
+	switch wrapped := err.(type) { //nolint:errorlint // the error tree is walked
+	case *files.ValidationError:
+		return []*files.ValidationError{wrapped}
+	case interface{ Unwrap() []error }:
+		var verrs []*files.ValidationError
+		for _, err := range wrapped.Unwrap() {
+			verrs = append(verrs, validationErrors(err)...)
+		}
+		return verrs
+	case interface{ Unwrap() error }:
+		return validationErrors(wrapped.Unwrap())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,221 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kickr-dev/engine/pkg/config"
+)
+
+type maintainer struct {
+	Name  string `json:"name"  toml:"name"  yaml:"name"`
+	Email string `json:"email" toml:"email" yaml:"email"`
+}
+
+type testconfig struct {
+	Name        string       `json:"name"                  toml:"name"                  yaml:"name"`
+	Maintainers []maintainer `json:"maintainers,omitempty" toml:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+	Port        int          `json:"port,omitempty"        toml:"port,omitempty"        yaml:"port,omitempty"`
+}
+
+const schema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": [ "name" ],
+	"properties": {
+		"name": { "type": "string" },
+		"port": { "type": "integer", "maximum": 65535 },
+		"maintainers": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": { "email": { "type": "string", "pattern": "^.+@.+$" } }
+			}
+		}
+	}
+}`
+
+func TestFind(t *testing.T) {
+	t.Run("error_not_found", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.xml": {}}
+
+		// Act
+		_, err := config.Find(fsys, ".kickr")
+
+		// Assert
+		assert.ErrorIs(t, err, config.ErrNotFound)
+	})
+
+	t.Run("success_extensions_order", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.json": {}, ".kickr.toml": {}, ".kickr.yml": {}}
+
+		// Act
+		src, err := config.Find(fsys, ".kickr")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, ".kickr.yml", src)
+	})
+
+	t.Run("success_explicit_extension", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{"config/.kickr.json": {}, "config/.kickr.yaml": {}}
+
+		// Act
+		src, err := config.Find(fsys, "config/.kickr.json")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "config/.kickr.json", src)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("error_not_found", func(t *testing.T) {
+		// Act
+		_, _, err := config.Load[testconfig](fstest.MapFS{}, ".kickr")
+
+		// Assert
+		assert.ErrorIs(t, err, config.ErrNotFound)
+	})
+
+	t.Run("error_unsupported_schema", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.yaml": {Data: []byte("name: name")}, "schema.xml": {}}
+
+		// Act
+		_, _, err := config.Load[testconfig](fsys, ".kickr", config.WithSchema(fsys, "schema.xml"))
+
+		// Assert
+		assert.ErrorContains(t, err, "unsupported schema format '.xml'")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			file     string
+			content  string
+			expected []string
+		}{
+			{
+				name:     "syntax_json",
+				file:     ".kickr.json",
+				content:  "{\n  \"name\": \"name\",\n  \"port\": ]\n}",
+				expected: []string{".kickr.json:3:11: "},
+			},
+			{
+				name:     "syntax_yaml",
+				file:     ".kickr.yaml",
+				content:  "name: name\nport: [8080\n",
+				expected: []string{".kickr.yaml:2:"},
+			},
+			{
+				name:     "decode_toml",
+				file:     ".kickr.toml",
+				content:  "name = 'name'\nport = 'port'\n",
+				expected: []string{".kickr.toml:2:"},
+			},
+			{
+				name:    "schema_json",
+				file:    ".kickr.json",
+				content: "{\n  \"name\": 1,\n  \"port\": 70000\n}",
+				expected: []string{
+					".kickr.json:2:11: at '/name': got number, want string",
+					".kickr.json:3:11: at '/port': maximum: got 70,000, want 65,535",
+				},
+			},
+			{
+				name:    "schema_toml",
+				file:    ".kickr.toml",
+				content: "port = 8080\n\n[[maintainers]]\nname = 'jane'\n\n[[maintainers]]\nemail = 'invalid'\n",
+				expected: []string{
+					".kickr.toml: at '/': missing property 'name'",
+					".kickr.toml:7:1: at '/maintainers/1/email': ",
+				},
+			},
+			{
+				name:    "schema_yaml",
+				file:    ".kickr.yml",
+				content: "name: name\nmaintainers:\n  - name: jane\n  - name: john\n    email: invalid\n",
+				expected: []string{
+					".kickr.yml:5:12: at '/maintainers/1/email': ",
+				},
+			},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Arrange
+				fsys := fstest.MapFS{
+					tc.file:             {Data: []byte(tc.content)},
+					"kickr.schema.json": {Data: []byte(schema)},
+				}
+
+				// Act
+				_, src, err := config.Load[testconfig](fsys, ".kickr", config.WithSchema(fsys, "kickr.schema.json"))
+
+				// Assert
+				assert.Equal(t, tc.file, src)
+				var cerr *config.Error
+				require.ErrorAs(t, err, &cerr)
+				for _, expected := range tc.expected {
+					assert.ErrorContains(t, err, expected)
+				}
+			})
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		expected := testconfig{
+			Name:        "name",
+			Maintainers: []maintainer{{Name: "jane", Email: "jane@example.com"}},
+			Port:        8080,
+		}
+
+		cases := map[string]string{
+			".kickr.json": `{"name": "name", "port": 8080, "maintainers": [{"name": "jane", "email": "jane@example.com"}]}`,
+			".kickr.toml": "name = 'name'\nport = 8080\n\n[[maintainers]]\nname = 'jane'\nemail = 'jane@example.com'\n",
+			".kickr.yaml": "name: name\nport: 8080\nmaintainers:\n  - name: jane\n    email: jane@example.com\n",
+		}
+		for file, content := range cases {
+			t.Run(file, func(t *testing.T) {
+				// Arrange
+				fsys := fstest.MapFS{
+					file:                {Data: []byte(content)},
+					"kickr.schema.json": {Data: []byte(schema)},
+				}
+
+				// Act
+				actual, src, err := config.Load[testconfig](fsys, ".kickr", config.WithSchema(fsys, "kickr.schema.json"))
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, file, src)
+				assert.Equal(t, expected, actual)
+			})
+		}
+	})
+}
+
+func TestError(t *testing.T) {
+	t.Run("unknown_position", func(t *testing.T) {
+		// Arrange
+		err := &config.Error{File: ".kickr.yml", Err: errors.New("an error")}
+
+		// Act & Assert
+		assert.EqualError(t, err, ".kickr.yml: an error")
+	})
+
+	t.Run("position", func(t *testing.T) {
+		// Arrange
+		err := &config.Error{File: ".kickr.yml", Line: 2, Column: 3, Property: "/name", Err: errors.New("an error")}
+
+		// Act & Assert
+		assert.EqualError(t, err, ".kickr.yml:2:3: at '/name': an error")
+	})
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ErrUnsupportedVersion is returned by Load when the configuration file version is newer
+// than the latest version given with WithMigrations (i.e. the file was written by a newer tool).
+var ErrUnsupportedVersion = errors.New("unsupported configuration version")
+
+// DefaultVersionKey is the default configuration file key holding its version.
+const DefaultVersionKey = "version"
+
+// Migration represents a configuration document migration from version From to version From+1.
+type Migration struct {
+	// From is the configuration version migrated.
+	From int
+
+	// Migrate migrates in place the raw configuration document (e.g. renaming keys or moving sections)
+	// before it's validated and decoded.
+	Migrate func(doc map[string]any) error
+}
+
+// WithMigrations sets the current version of the configuration and the migrations to apply
+// on older configuration files (a file without version is considered as version 0).
+//
+// Migrations are applied one version after the other on the raw document before its validation and decoding,
+// the document version is then set to the current one.
+// Load returns ErrUnsupportedVersion when the file version is greater than the current version.
+func WithMigrations(version int, migrations ...Migration) LoadOption {
+	return func(lo loadOptions) loadOptions {
+		lo.version = version
+		lo.migrations = migrations
+		return lo
+	}
+}
+
+// WithVersionKey sets the configuration file key holding its version (default DefaultVersionKey).
+func WithVersionKey(key string) LoadOption {
+	return func(lo loadOptions) loadOptions {
+		lo.versionKey = key
+		return lo
+	}
+}
+
+// WithRewrite sets whether a migrated configuration file is rewritten (see Write) once successfully loaded,
+// destdir being the directory matching Load fsys.
+//
+// Note that comments and formatting of the original file aren't kept.
+func WithRewrite(destdir string) LoadOption {
+	return func(lo loadOptions) loadOptions {
+		lo.rewrite = destdir
+		return lo
+	}
+}
+
+// migrate applies on input doc the migrations from its version to the current one.
+//
+// It returns true when at least one migration was applied.
+func (lo loadOptions) migrate(src string, content []byte, f format, doc any) (bool, error) {
+	if lo.migrations == nil && lo.version == 0 {
+		return false, nil
+	}
+
+	values, ok := doc.(map[string]any)
+	if !ok {
+		return false, &Error{File: src, Err: errors.New("configuration must be an object to be migrated")}
+	}
+
+	version, err := versionOf(values[lo.versionKey])
+	if err != nil {
+		line, column := f.locate(content, doc, []string{lo.versionKey})
+		return false, &Error{File: src, Line: line, Column: column, Property: "/" + lo.versionKey, Err: err}
+	}
+	if version > lo.version {
+		line, column := f.locate(content, doc, []string{lo.versionKey})
+		return false, &Error{
+			File:     src,
+			Line:     line,
+			Column:   column,
+			Property: "/" + lo.versionKey,
+			Err:      fmt.Errorf("%w: version %d is newer than supported version %d", ErrUnsupportedVersion, version, lo.version),
+		}
+	}
+	if version == lo.version {
+		return false, nil
+	}
+
+	for ; version < lo.version; version++ {
+		i := -1
+		for j, migration := range lo.migrations {
+			if migration.From == version {
+				i = j
+				break
+			}
+		}
+		if i < 0 || lo.migrations[i].Migrate == nil {
+			return false, &Error{File: src, Err: fmt.Errorf("missing migration from version %d", version)}
+		}
+		if err := lo.migrations[i].Migrate(values); err != nil {
+			return false, &Error{File: src, Err: fmt.Errorf("migrate from version %d: %w", version, err)}
+		}
+	}
+	values[lo.versionKey] = lo.version
+	return true, nil
+}
+
+// versionOf returns the integer version of input raw value (0 when nil).
+func versionOf(value any) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanInt() && rv.Int() >= 0 && rv.Int() <= math.MaxInt32:
+		return int(rv.Int()), nil
+	case rv.CanUint() && rv.Uint() <= math.MaxInt32:
+		return int(rv.Uint()), nil //nolint:gosec // checked above
+	case rv.CanFloat() && rv.Float() >= 0 && rv.Float() <= math.MaxInt32 && rv.Float() == math.Trunc(rv.Float()):
+		return int(rv.Float()), nil
+	default:
+		return 0, fmt.Errorf("invalid version '%v', must be a positive integer", value)
+	}
+}
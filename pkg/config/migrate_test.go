@@ -0,0 +1,178 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kickr-dev/engine/pkg/config"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+type versionedconfig struct {
+	Version int    `json:"version" toml:"version" yaml:"version"`
+	Name    string `json:"name"    toml:"name"    yaml:"name"`
+	Owner   string `json:"owner"   toml:"owner"   yaml:"owner"`
+}
+
+func TestWithMigrations(t *testing.T) {
+	// version 0 -> 1: 'project' is renamed 'name'
+	// version 1 -> 2: 'maintainer.name' is moved to 'owner'
+	migrations := []config.Migration{
+		{
+			From: 1,
+			Migrate: func(doc map[string]any) error {
+				maintainer, ok := doc["maintainer"].(map[string]any)
+				if !ok {
+					return errors.New("missing maintainer")
+				}
+				doc["owner"] = maintainer["name"]
+				delete(doc, "maintainer")
+				return nil
+			},
+		},
+		{
+			From: 0,
+			Migrate: func(doc map[string]any) error {
+				doc["name"] = doc["project"]
+				delete(doc, "project")
+				return nil
+			},
+		},
+	}
+
+	t.Run("error_newer_version", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.yml": {Data: []byte("name: name\nversion: 3\n")}}
+
+		// Act
+		_, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2, migrations...))
+
+		// Assert
+		assert.ErrorIs(t, err, config.ErrUnsupportedVersion)
+		assert.EqualError(t, err, ".kickr.yml:2:10: at '/version': unsupported configuration version: version 3 is newer than supported version 2")
+	})
+
+	t.Run("error_invalid_version", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.json": {Data: []byte(`{"version": "v1"}`)}}
+
+		// Act
+		_, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2, migrations...))
+
+		// Assert
+		assert.ErrorContains(t, err, "invalid version 'v1', must be a positive integer")
+	})
+
+	t.Run("error_missing_migration", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.yml": {Data: []byte("name: name\nversion: 1\n")}}
+
+		// Act
+		_, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2))
+
+		// Assert
+		assert.ErrorContains(t, err, "missing migration from version 1")
+	})
+
+	t.Run("error_migrate", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.yml": {Data: []byte("name: name\nversion: 1\n")}}
+
+		// Act
+		_, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2, migrations...))
+
+		// Assert
+		assert.ErrorContains(t, err, "migrate from version 1: missing maintainer")
+	})
+
+	t.Run("error_not_object", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.yml": {Data: []byte("- name\n")}}
+
+		// Act
+		_, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2, migrations...))
+
+		// Assert
+		assert.ErrorContains(t, err, "configuration must be an object to be migrated")
+	})
+
+	t.Run("success_current_version", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.toml": {Data: []byte("version = 2\nname = 'name'\nowner = 'jane'\n")}}
+
+		// Act
+		actual, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2, migrations...))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, versionedconfig{Version: 2, Name: "name", Owner: "jane"}, actual)
+	})
+
+	t.Run("success_migrated", func(t *testing.T) {
+		// Arrange
+		content := "project: name\nmaintainer:\n  name: jane\n"
+		fsys := fstest.MapFS{".kickr.yml": {Data: []byte(content)}}
+
+		// Act
+		actual, _, err := config.Load[versionedconfig](fsys, ".kickr", config.WithMigrations(2, migrations...))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, versionedconfig{Version: 2, Name: "name", Owner: "jane"}, actual)
+		assert.Equal(t, content, string(fsys[".kickr.yml"].Data))
+	})
+
+	t.Run("success_version_key", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{".kickr.json": {Data: []byte(`{"schema": 1, "name": "name", "maintainer": {"name": "jane"}}`)}}
+
+		// Act
+		actual, _, err := config.Load[versionedconfig](fsys, ".kickr",
+			config.WithMigrations(2, migrations...),
+			config.WithVersionKey("schema"))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, versionedconfig{Name: "name", Owner: "jane"}, actual)
+	})
+
+	t.Run("success_rewrite", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		src := filepath.Join(dir, ".kickr.json")
+		require.NoError(t, os.WriteFile(src, []byte(`{"version": 1, "name": "name", "maintainer": {"name": "jane"}}`), files.RwRR))
+
+		// Act
+		_, _, err := config.Load[versionedconfig](os.DirFS(dir), ".kickr",
+			config.WithMigrations(2, migrations...),
+			config.WithRewrite(dir))
+
+		// Assert
+		require.NoError(t, err)
+		bytes, err := os.ReadFile(src)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"version": 2, "name": "name", "owner": "jane"}`, string(bytes))
+	})
+
+	t.Run("error_schema_migrated", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{
+			".kickr.yml":        {Data: []byte("project: 1\nmaintainer:\n  name: jane\n")},
+			"kickr.schema.json": {Data: []byte(schema)},
+		}
+
+		// Act
+		_, _, err := config.Load[versionedconfig](fsys, ".kickr",
+			config.WithMigrations(2, migrations...),
+			config.WithSchema(fsys, "kickr.schema.json"))
+
+		// Assert
+		assert.ErrorContains(t, err, ".kickr.yml: at '/name': got number, want string")
+	})
+}
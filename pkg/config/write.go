@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+// Write writes the input configuration into dst, the format being chosen from dst extension
+// (".yaml", ".yml", ".toml" or ".json").
+//
+// Keys are written in a stable order (structs fields in their declaration order and maps keys sorted),
+// for configuration changes to diff cleanly.
+func Write(dst string, config any) error {
+	f, ok := formatOf(dst)
+	if !ok {
+		return fmt.Errorf("unsupported configuration format '%s'", filepath.Ext(dst))
+	}
+
+	content, err := f.marshal(config)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), files.RwxRxRxRx); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+	if err := os.WriteFile(dst, content, files.RwRR&^files.Umask()); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kickr-dev/engine/pkg/config"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("error_unsupported_format", func(t *testing.T) {
+		// Act
+		err := config.Write(filepath.Join(t.TempDir(), ".kickr.xml"), testconfig{})
+
+		// Assert
+		assert.ErrorContains(t, err, "unsupported configuration format '.xml'")
+	})
+
+	t.Run("error_write_file", func(t *testing.T) {
+		// Arrange
+		dst := filepath.Join(t.TempDir(), ".kickr.yml")
+		require.NoError(t, os.Mkdir(dst, files.RwxRxRxRx))
+
+		// Act
+		err := config.Write(dst, testconfig{})
+
+		// Assert
+		assert.ErrorContains(t, err, "write file")
+	})
+
+	t.Run("success_stable_order", func(t *testing.T) {
+		// Arrange
+		values := map[string]any{"zone": "eu", "name": "name", "labels": map[string]any{"team": "core", "app": "engine"}}
+
+		cases := map[string]string{
+			".kickr.json": "{\n  \"labels\": {\n    \"app\": \"engine\",\n    \"team\": \"core\"\n  },\n  \"name\": \"name\",\n  \"zone\": \"eu\"\n}\n",
+			".kickr.toml": "name = 'name'\nzone = 'eu'\n\n[labels]\napp = 'engine'\nteam = 'core'\n",
+			".kickr.yml":  "labels:\n  app: engine\n  team: core\nname: name\nzone: eu\n",
+		}
+		for file, expected := range cases {
+			t.Run(file, func(t *testing.T) {
+				dst := filepath.Join(t.TempDir(), "dir", file)
+
+				for range 3 {
+					// Act
+					err := config.Write(dst, values)
+
+					// Assert
+					require.NoError(t, err)
+					bytes, err := os.ReadFile(dst)
+					require.NoError(t, err)
+					assert.Equal(t, expected, string(bytes))
+				}
+			})
+		}
+	})
+
+	t.Run("success_load", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		expected := testconfig{Name: "name", Maintainers: []maintainer{{Name: "jane"}}, Port: 8080}
+
+		// Act
+		err := config.Write(filepath.Join(dir, ".kickr.toml"), expected)
+
+		// Assert
+		require.NoError(t, err)
+		actual, _, err := config.Load[testconfig](os.DirFS(dir), ".kickr")
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
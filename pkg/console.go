@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+)
+
+// ConsoleOption represents a function that can be given when calling NewConsoleLogger to tune its behavior.
+type ConsoleOption func(o consoleOptions) consoleOptions
+
+// ConsoleLevel returns a ConsoleOption setting the minimum level of logged messages (default slog.LevelInfo).
+//
+// Levels map to Logger methods: slog.LevelDebug for Debugf, slog.LevelInfo for Infof,
+// slog.LevelWarn for Warnf and slog.LevelError for Errorf.
+func ConsoleLevel(level slog.Level) ConsoleOption {
+	return func(o consoleOptions) consoleOptions {
+		o.level = level
+		return o
+	}
+}
+
+// ConsolePlain returns a ConsoleOption disabling colors whatever the output is, e.g. for CI environments.
+func ConsolePlain(plain bool) ConsoleOption {
+	return func(o consoleOptions) consoleOptions {
+		o.plain = plain
+		return o
+	}
+}
+
+// ConsolePrefixes returns a ConsoleOption overriding the prefix of messages for input levels
+// (default "DEBUG", "INFO", "WARN" and "ERROR").
+func ConsolePrefixes(prefixes map[slog.Level]string) ConsoleOption {
+	return func(o consoleOptions) consoleOptions {
+		for level, prefix := range prefixes {
+			o.prefixes[level] = prefix
+		}
+		return o
+	}
+}
+
+// consoleOptions represents the struct with all available options in NewConsoleLogger function.
+type consoleOptions struct {
+	level    slog.Level
+	plain    bool
+	prefixes map[slog.Level]string
+}
+
+// newConsoleOptions creates a new option struct with all input ConsoleOption functions while taking care of default values.
+func newConsoleOptions(opts ...ConsoleOption) consoleOptions {
+	co := consoleOptions{
+		level: slog.LevelInfo,
+		prefixes: map[slog.Level]string{
+			slog.LevelDebug: "DEBUG",
+			slog.LevelInfo:  "INFO",
+			slog.LevelWarn:  "WARN",
+			slog.LevelError: "ERROR",
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			co = opt(co)
+		}
+	}
+	return co
+}
+
+// consoleStyles are the lipgloss styles of each level prefix.
+var consoleStyles = map[slog.Level]lipgloss.Style{
+	slog.LevelDebug: lipgloss.NewStyle().Faint(true),
+	slog.LevelInfo:  lipgloss.NewStyle().Foreground(lipgloss.Color("12")),
+	slog.LevelWarn:  lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
+	slog.LevelError: lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true),
+}
+
+type consoleLogger struct {
+	mu       sync.Mutex
+	level    slog.Level
+	prefixes map[slog.Level]string
+	writer   io.Writer
+}
+
+var _ Logger = (*consoleLogger)(nil) // ensure interface is implemented
+
+// NewConsoleLogger creates a Logger writing one line per message into input writer (e.g. os.Stderr),
+// prefixed by the message level.
+//
+// Messages below the minimum level (see ConsoleLevel) are dropped.
+// Prefixes are colored with lipgloss when writer is a terminal (and NO_COLOR isn't set) unless ConsolePlain is given.
+//
+// It's safe for concurrent use, as required by Generate.
+func NewConsoleLogger(writer io.Writer, opts ...ConsoleOption) Logger {
+	co := newConsoleOptions(opts...)
+
+	prefixes := make(map[slog.Level]string, len(co.prefixes))
+	for level, prefix := range co.prefixes {
+		if !co.plain {
+			prefix = consoleStyles[level].Render(prefix)
+		}
+		prefixes[level] = prefix
+	}
+	if !co.plain {
+		// downsample (or strip) colors according to writer capabilities
+		writer = colorprofile.NewWriter(writer, os.Environ())
+	}
+	return &consoleLogger{level: co.level, prefixes: prefixes, writer: writer}
+}
+
+// Debugf implements Logger.
+func (c *consoleLogger) Debugf(format string, args ...any) {
+	c.printf(slog.LevelDebug, format, args...)
+}
+
+// Errorf implements Logger.
+func (c *consoleLogger) Errorf(format string, args ...any) {
+	c.printf(slog.LevelError, format, args...)
+}
+
+// Infof implements Logger.
+func (c *consoleLogger) Infof(format string, args ...any) {
+	c.printf(slog.LevelInfo, format, args...)
+}
+
+// Warnf implements Logger.
+func (c *consoleLogger) Warnf(format string, args ...any) {
+	c.printf(slog.LevelWarn, format, args...)
+}
+
+func (c *consoleLogger) printf(level slog.Level, format string, args ...any) {
+	if level < c.level {
+		return
+	}
+
+	line := fmt.Appendf(nil, format, args...)
+	if prefix := c.prefixes[level]; prefix != "" {
+		line = fmt.Appendf(nil, "%s %s", prefix, line)
+	}
+	line = append(line, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = c.writer.Write(line)
+}
@@ -0,0 +1,94 @@
+package engine_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	engine "github.com/kickr-dev/engine/pkg"
+)
+
+func TestNewConsoleLogger(t *testing.T) {
+	log := func(logger engine.Logger) {
+		logger.Debugf("debug %d", 1)
+		logger.Infof("info %d", 2)
+		logger.Warnf("warn %d", 3)
+		logger.Errorf("error %d", 4)
+	}
+
+	t.Run("success_default_level", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		logger := engine.NewConsoleLogger(&buf, engine.ConsolePlain(true))
+
+		// Act
+		log(logger)
+
+		// Assert
+		assert.Equal(t, "INFO info 2\nWARN warn 3\nERROR error 4\n", buf.String())
+	})
+
+	t.Run("success_level_and_prefixes", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		logger := engine.NewConsoleLogger(&buf,
+			engine.ConsolePlain(true),
+			engine.ConsoleLevel(slog.LevelDebug),
+			engine.ConsolePrefixes(map[slog.Level]string{slog.LevelDebug: "[debug]", slog.LevelInfo: ""}))
+
+		// Act
+		log(logger)
+
+		// Assert
+		assert.Equal(t, "[debug] debug 1\ninfo 2\nWARN warn 3\nERROR error 4\n", buf.String())
+	})
+
+	t.Run("success_no_tty", func(t *testing.T) {
+		// Arrange
+		t.Setenv("CLICOLOR_FORCE", "")
+		var buf bytes.Buffer
+		logger := engine.NewConsoleLogger(&buf, engine.ConsoleLevel(slog.LevelError))
+
+		// Act
+		log(logger)
+
+		// Assert
+		assert.Equal(t, "ERROR error 4\n", buf.String())
+	})
+
+	t.Run("success_colored", func(t *testing.T) {
+		// Arrange
+		t.Setenv("CLICOLOR_FORCE", "1")
+		t.Setenv("NO_COLOR", "")
+		t.Setenv("TERM", "xterm-256color")
+		var buf bytes.Buffer
+		logger := engine.NewConsoleLogger(&buf, engine.ConsoleLevel(slog.LevelError))
+
+		// Act
+		log(logger)
+
+		// Assert
+		assert.Contains(t, buf.String(), "\x1b[")
+		assert.True(t, strings.HasSuffix(buf.String(), " error 4\n"))
+	})
+
+	t.Run("success_concurrent", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		logger := engine.NewConsoleLogger(&buf, engine.ConsolePlain(true))
+
+		// Act
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() { logger.Infof("message") })
+		}
+		wg.Wait()
+
+		// Assert
+		assert.Equal(t, strings.Repeat("INFO message\n", 10), buf.String())
+	})
+}
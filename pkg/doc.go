@@ -11,7 +11,10 @@ It contains two main functions, Initialize and Generate which split project init
 		ctx := context.Background()
 		destdir, _ := os.Getwd()
 
-		config, err := engine.Initialize(ctx, destdir, engine.WithFormGroups(License))
+		config, err := engine.Initialize(ctx,
+			engine.WithDestdir[config](destdir),
+			engine.WithParsers(ParserGit), // pre-populates config with the existing repository values
+			engine.WithFormGroups(License))
 		// handle err
 	}
 
@@ -79,7 +82,9 @@ It contains two main functions, Initialize and Generate which split project init
 				// GeneratePolicy can be given to tune generation, see the appropriate documentation
 				GeneratePolicy: engine.PolicyAlways,
 				Globs:          engine.GlobsWithPart(name),
-				Out:            name,
+				// Notice injects "# Code generated by kickr; DO NOT EDIT." at the top of the generated file
+				Notice: "kickr",
+				Out:    name,
 				// Patches applies further transformations to the generated file after it's written
 				Patches: []string{"path/to/file.patch"},
 				// Remove can be given to remove a specific file in some specific case instead of generating it
@@ -2,19 +2,27 @@ package engine
 
 import (
 	"bytes"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
 	"regexp"
+	"strings"
 )
 
-var (
-	delimiterOnlyLine = regexp.MustCompile(`(?m)^\s*(?:/\*|\*/|<!--|-->|#!.*)\s*$\n?`)
-	generatedLine     = regexp.MustCompile(`(?m)^.*` + generated.String() + `.*$\n?`)
-)
+var delimiterOnlyLine = regexp.MustCompile(`(?m)^\s*(?:/\*|\*/|<!--|-->|#!.*)\s*$\n?`)
+
+// noticeLine returns the regexp matching a whole line containing the input generated notice regexp.
+func noticeLine(notice *regexp.Regexp) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^.*(?:` + notice.String() + `).*$\n?`)
+}
+
+var generatedLine = noticeLine(generated)
 
 // EmptyPolicy defines the policy to apply when a generated file is empty.
 //
 // By default, the policy is set to PolicyRemove,
 // meaning that a generated file will be removed if it is empty
-// or contains only the notice "Code generated by [\w\-\/]+; DO NOT EDIT.".
+// or contains only a generated notice (see GeneratedBy).
 type EmptyPolicy int
 
 const (
@@ -43,5 +51,126 @@ func IsEmpty(content []byte, policy EmptyPolicy) bool {
 	if len(content) == 0 {
 		return true
 	}
-	return len(bytes.TrimSpace(delimiterOnlyLine.ReplaceAll(generatedLine.ReplaceAll(content, nil), nil))) == 0
+	for _, line := range noticeLines() {
+		content = line.ReplaceAll(content, nil)
+	}
+	return len(bytes.TrimSpace(delimiterOnlyLine.ReplaceAll(content, nil))) == 0
+}
+
+// EmptyFunc is the function signature to detect whether a generated content is empty for a given file type.
+//
+// It's only called when the EmptyPolicy is PolicyRemove.
+type EmptyFunc func(content []byte) bool
+
+// emptyFuncs is the association between a file extension (or name) and its built-in EmptyFunc.
+var emptyFuncs = map[string]EmptyFunc{
+	".bash":         EmptyShell,
+	".dockerfile":   EmptyDockerfile,
+	".go":           EmptyGo,
+	".markdown":     EmptyMarkdown,
+	".md":           EmptyMarkdown,
+	".sh":           EmptyShell,
+	".toml":         EmptyTOML,
+	".yaml":         EmptyYAML,
+	".yml":          EmptyYAML,
+	".zsh":          EmptyShell,
+	"Containerfile": EmptyDockerfile,
+	"Dockerfile":    EmptyDockerfile,
+}
+
+// EmptyFuncFor returns the EmptyFunc associated to input out file name (or extension).
+//
+// EmptyFunc provided with WithEmptyFuncs option take precedence over built-in ones
+// (for YAML, TOML, Go, shell, Markdown and Dockerfiles).
+// Other files fall back to IsEmpty default behavior.
+func EmptyFuncFor(out string) EmptyFunc {
+	name := filepath.Base(out)
+	keys := []string{name, strings.ToLower(filepath.Ext(name))}
+	if prefix, _, ok := strings.Cut(name, "."); ok && (prefix == "Dockerfile" || prefix == "Containerfile") {
+		keys = append(keys, prefix)
+	}
+
+	custom := customEmptyFuncs()
+	for _, key := range keys {
+		if empty, ok := custom[key]; ok {
+			return empty
+		}
+	}
+	for _, key := range keys {
+		if empty, ok := emptyFuncs[key]; ok {
+			return empty
+		}
+	}
+	return func(content []byte) bool { return IsEmpty(content, PolicyRemove) }
+}
+
+// EmptyYAML returns true if the YAML content contains only comments,
+// directives (e.g. %YAML 1.2), document markers (--- and ...) or whitespaces.
+func EmptyYAML(content []byte) bool {
+	return onlyLines(content, func(line []byte) bool {
+		return bytes.HasPrefix(line, []byte("#")) || bytes.HasPrefix(line, []byte("%")) ||
+			bytes.Equal(line, []byte("---")) || bytes.Equal(line, []byte("..."))
+	})
+}
+
+// EmptyTOML returns true if the TOML content contains only comments or whitespaces.
+func EmptyTOML(content []byte) bool {
+	return onlyLines(content, hashComment)
+}
+
+// EmptyShell returns true if the shell content contains only comments (including shebang) or whitespaces.
+func EmptyShell(content []byte) bool {
+	return onlyLines(content, hashComment)
+}
+
+// EmptyDockerfile returns true if the Dockerfile content contains only comments (including parser directives) or whitespaces.
+func EmptyDockerfile(content []byte) bool {
+	return onlyLines(content, hashComment)
+}
+
+var htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// EmptyMarkdown returns true if the Markdown content contains only a front matter (YAML or TOML), HTML comments or whitespaces.
+func EmptyMarkdown(content []byte) bool {
+	content = content[frontMatterLength(content):]
+	return len(bytes.TrimSpace(htmlComment.ReplaceAll(content, nil))) == 0
+}
+
+// EmptyGo returns true if the Go content contains only comments, a package clause or whitespaces.
+func EmptyGo(content []byte) bool {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(content))
+
+	var s scanner.Scanner
+	s.Init(file, content, nil, 0) // comments are skipped without scanner.ScanComments mode
+
+	var previous token.Token
+	for {
+		_, tok, _ := s.Scan()
+		switch {
+		case tok == token.EOF:
+			return s.ErrorCount == 0 && previous != token.PACKAGE
+		case tok == token.PACKAGE && previous == token.ILLEGAL, // first token
+			tok == token.IDENT && previous == token.PACKAGE,
+			tok == token.SEMICOLON && previous == token.IDENT:
+		default:
+			return false
+		}
+		previous = tok
+	}
+}
+
+// hashComment returns true if the input line is a comment starting with '#'.
+func hashComment(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("#"))
+}
+
+// onlyLines returns true if all non blank lines of content satisfy the input ignored function.
+func onlyLines(content []byte, ignored func(line []byte) bool) bool {
+	for line := range bytes.Lines(content) {
+		if line = bytes.TrimSpace(line); len(line) > 0 && !ignored(line) {
+			return false
+		}
+	}
+	return true
 }
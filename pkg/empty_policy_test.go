@@ -1,6 +1,7 @@
 package engine_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,3 +45,71 @@ func TestIsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestIsEmptyConfiguredNotices(t *testing.T) {
+	// Arrange
+	engine.Configure(engine.WithGeneratedNotices(regexp.MustCompile(`@generated by (\S+)`)))
+	t.Cleanup(func() { engine.Configure() })
+
+	// Act
+	ok := engine.IsEmpty([]byte("# @generated by protoc\n"), engine.PolicyRemove)
+
+	// Assert
+	assert.True(t, ok)
+}
+
+func TestEmptyFuncFor(t *testing.T) {
+	cases := []struct {
+		name    string
+		out     string
+		content string
+		empty   bool
+	}{
+		{"yaml_comments_and_markers", "file.yml", "%YAML 1.2\n---\n# comment\n...\n", true},
+		{"yaml_content", "file.yaml", "---\nkey: value\n", false},
+		{"toml_comments", "file.toml", "# Code generated by kickr; DO NOT EDIT.\n\n# comment\n", true},
+		{"toml_content", "file.toml", "# comment\n[section]\n", false},
+		{"go_package_only", "file.go", "// Code generated by kickr; DO NOT EDIT.\n\n/* doc */\npackage main\n", true},
+		{"go_comments_only", "file.go", "// comment\n", true},
+		{"go_content", "file.go", "package main\n\nimport \"fmt\"\n", false},
+		{"go_invalid", "file.go", "package\n", false},
+		{"shell_shebang_comments", "script.sh", "#!/usr/bin/env bash\n# comment\n", true},
+		{"shell_content", "script.sh", "#!/usr/bin/env bash\nset -e\n", false},
+		{"markdown_comments", "README.md", "<!-- Code generated by kickr; DO NOT EDIT. -->\n<!--\nmultiline\n-->\n", true},
+		{"markdown_content", "README.md", "<!-- comment -->\n# Title\n", false},
+		{"markdown_front_matter", "README.md", "---\ntitle: x\n---\n<!-- Code generated by kickr; DO NOT EDIT. -->\n", true},
+		{"markdown_toml_front_matter", "index.md", "+++\ntitle = 'x'\n+++\n", true},
+		{"markdown_front_matter_content", "README.md", "---\ntitle: x\n---\n# Title\n", false},
+		{"dockerfile_comments", "Dockerfile.dev", "# syntax=docker/dockerfile:1\n# comment\n", true},
+		{"dockerfile_content", "Dockerfile", "FROM scratch\n", false},
+		{"default_notice", "file.txt", "# Code generated by kickr; DO NOT EDIT.\n", true},
+		{"default_comments", "file.txt", "# comment\n", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			empty := engine.EmptyFuncFor(tc.out)([]byte(tc.content))
+
+			// Assert
+			assert.Equal(t, tc.empty, empty)
+		})
+	}
+
+	t.Run("success_configured", func(t *testing.T) {
+		// Arrange
+		engine.Configure(engine.WithEmptyFuncs(map[string]engine.EmptyFunc{
+			".yml": func([]byte) bool { return false },
+			".txt": engine.EmptyShell,
+		}))
+		t.Cleanup(func() { engine.Configure() })
+
+		// Act
+		yaml := engine.EmptyFuncFor("file.yml")(nil)
+		txt := engine.EmptyFuncFor("file.txt")([]byte("# comment\n"))
+
+		// Assert
+		assert.False(t, yaml)
+		assert.True(t, txt)
+	})
+}
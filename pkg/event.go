@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// EventKind represents the kind of an Event emitted during Generate.
+type EventKind int
+
+const (
+	// EventParserStarted is emitted right before a parser is run.
+	EventParserStarted EventKind = iota + 1
+	// EventParserFinished is emitted right after a parser is run, with its error (if any).
+	EventParserFinished
+	// EventGeneratorStarted is emitted right before a generator is run.
+	EventGeneratorStarted
+	// EventGeneratorFinished is emitted right after a generator is run, with its error (if any).
+	EventGeneratorFinished
+	// EventTemplateRendered is emitted once a template is executed, before it's written (or removed when empty).
+	EventTemplateRendered
+	// EventFileWritten is emitted once a file (or a symbolic link) is written.
+	EventFileWritten
+	// EventFileSkipped is emitted when a file isn't generated, with the reason why.
+	EventFileSkipped
+	// EventFileRemoved is emitted once a file is removed (because of Template.Remove or because it was empty).
+	EventFileRemoved
+	// EventPatchApplied is emitted once a patch is applied on a file.
+	EventPatchApplied
+	// EventFileRead is emitted when a template reads a destination file (or directory) with a filesystem function
+	// (e.g. "readFile"), for instance to know the inputs of a generated file.
+	EventFileRead
+)
+
+var eventKinds = map[EventKind]string{
+	EventParserStarted:     "parser_started",
+	EventParserFinished:    "parser_finished",
+	EventGeneratorStarted:  "generator_started",
+	EventGeneratorFinished: "generator_finished",
+	EventTemplateRendered:  "template_rendered",
+	EventFileWritten:       "file_written",
+	EventFileSkipped:       "file_skipped",
+	EventFileRemoved:       "file_removed",
+	EventPatchApplied:      "patch_applied",
+	EventFileRead:          "file_read",
+}
+
+// String returns the snake case representation of the EventKind, e.g. "file_written".
+func (k EventKind) String() string {
+	if s, ok := eventKinds[k]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k EventKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Event represents a step of Generate, given to the EventSink configured with WithEventSink.
+type Event struct {
+	// Err is the error of a finished parser or generator, if any.
+	Err error
+
+	// Index is the index (in Generate input slices) of the parser or generator emitting the event.
+	//
+	// It's -1 for events emitted outside of Generate (e.g. when calling ApplyTemplate directly).
+	Index int
+
+	// Kind is the kind of event.
+	Kind EventKind
+
+	// Patch is the name of the patch applied with EventPatchApplied.
+	Patch string
+
+	// Path is the file concerned by the event (joined with the generation destination directory).
+	Path string
+
+	// Reason is the reason why a file was skipped with EventFileSkipped,
+	// or the template function reading a file with EventFileRead.
+	Reason string
+}
+
+// EventSink is the function receiving all events emitted during Generate.
+//
+// Calls are serialized by the engine, as such events of a given generator are received in order
+// (events of different generators are interleaved since they run concurrently).
+// A sink must return quickly since it blocks generation while processing an event.
+type EventSink func(event Event)
+
+// NewJSONEventSink returns an EventSink writing each event as a JSON line into input writer,
+// e.g. for IDE integrations:
+//
+//	{"kind":"file_written","index":0,"path":"destdir/README.md"}
+func NewJSONEventSink(writer io.Writer) EventSink {
+	encoder := json.NewEncoder(writer)
+	return func(event Event) {
+		line := jsonEvent{
+			Index:  event.Index,
+			Kind:   event.Kind,
+			Patch:  event.Patch,
+			Path:   event.Path,
+			Reason: event.Reason,
+		}
+		if event.Err != nil {
+			line.Error = event.Err.Error()
+		}
+		if err := encoder.Encode(line); err != nil {
+			GetLogger().Warnf("failed to write event '%s': %v", event.Kind, err)
+		}
+	}
+}
+
+// jsonEvent is the JSON representation of an Event.
+type jsonEvent struct {
+	Error  string    `json:"error,omitempty"`
+	Index  int       `json:"index"`
+	Kind   EventKind `json:"kind"`
+	Patch  string    `json:"patch,omitempty"`
+	Path   string    `json:"path,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// indexKey is the context key holding the index of the running parser or generator.
+type indexKey struct{}
+
+// withIndex returns a copy of ctx holding input parser or generator index.
+func withIndex(ctx context.Context, index int) context.Context {
+	return context.WithValue(ctx, indexKey{}, index)
+}
+
+// indexFrom returns the index of the parser or generator running in ctx, -1 if there's none.
+func indexFrom(ctx context.Context) int {
+	if ctx == nil {
+		return -1
+	}
+	if index, ok := ctx.Value(indexKey{}).(int); ok {
+		return index
+	}
+	return -1
+}
+
+// sinkMu serializes EventSink calls.
+var sinkMu sync.Mutex
+
+// emit sends input event to the configured EventSink (if any),
+// with the index of the parser or generator running in ctx.
+func emit(ctx context.Context, event Event) {
+	sink := eventSink()
+	if sink == nil {
+		return
+	}
+
+	event.Index = indexFrom(ctx)
+
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sink(event)
+}
@@ -0,0 +1,109 @@
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+func TestEventKindString(t *testing.T) {
+	cases := []struct {
+		kind     engine.EventKind
+		expected string
+	}{
+		{kind: engine.EventParserStarted, expected: "parser_started"},
+		{kind: engine.EventFileWritten, expected: "file_written"},
+		{kind: engine.EventPatchApplied, expected: "patch_applied"},
+		{kind: engine.EventFileRead, expected: "file_read"},
+		{kind: 0, expected: "unknown"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			// Act
+			actual := tc.kind.String()
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestGenerateEvents(t *testing.T) {
+	// Arrange
+	destdir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(destdir, "file.txt.tmpl"), []byte("content"), files.RwRR))
+	require.NoError(t, os.WriteFile(filepath.Join(destdir, "manual.txt"), []byte("manual"), files.RwRR))
+
+	var events []engine.Event
+	engine.Configure(engine.WithEventSink(func(event engine.Event) { events = append(events, event) }))
+	t.Cleanup(func() { engine.Configure() })
+
+	parser := func(context.Context, string, *testconfig) error { return nil }
+	generator := engine.GeneratorTemplates(os.DirFS(destdir), []engine.Template[testconfig]{
+		{Globs: []string{"file.txt.tmpl"}, Out: "file.txt"},
+		{Globs: []string{"file.txt.tmpl"}, Out: "manual.txt"},
+		{Globs: []string{"file.txt.tmpl"}, Out: "removed.txt", Remove: func(testconfig) bool { return true }},
+	})
+
+	// Act
+	err := engine.Generate(t.Context(), destdir, testconfig{},
+		[]engine.Parser[testconfig]{parser},
+		[]engine.Generator[testconfig]{generator})
+
+	// Assert
+	require.NoError(t, err)
+	expected := []engine.Event{
+		{Index: 0, Kind: engine.EventParserStarted},
+		{Index: 0, Kind: engine.EventParserFinished},
+		{Index: 0, Kind: engine.EventGeneratorStarted},
+		{Index: 0, Kind: engine.EventTemplateRendered, Path: filepath.Join(destdir, "file.txt")},
+		{Index: 0, Kind: engine.EventFileWritten, Path: filepath.Join(destdir, "file.txt")},
+		{Index: 0, Kind: engine.EventFileSkipped, Path: filepath.Join(destdir, "manual.txt"), Reason: "it already exists (or was modified manually)"},
+		{Index: 0, Kind: engine.EventGeneratorFinished},
+	}
+	assert.Equal(t, expected, events)
+}
+
+func TestApplyTemplateEvents(t *testing.T) {
+	// Arrange
+	destdir := t.TempDir()
+	out := filepath.Join(destdir, "file.txt")
+	require.NoError(t, os.WriteFile(out, []byte("content"), files.RwRR))
+
+	var events []engine.Event
+	engine.Configure(engine.WithEventSink(func(event engine.Event) { events = append(events, event) }))
+	t.Cleanup(func() { engine.Configure() })
+
+	template := engine.Template[testconfig]{Out: "file.txt", Remove: func(testconfig) bool { return true }}
+
+	// Act
+	err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []engine.Event{{Index: -1, Kind: engine.EventFileRemoved, Path: out}}, events)
+}
+
+func TestNewJSONEventSink(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	sink := engine.NewJSONEventSink(&buf)
+
+	// Act
+	sink(engine.Event{Index: 1, Kind: engine.EventFileSkipped, Path: "file.txt", Reason: "it would be empty"})
+	sink(engine.Event{Index: 2, Kind: engine.EventGeneratorFinished, Err: errors.New("some error")})
+
+	// Assert
+	expected := `{"index":1,"kind":"file_skipped","path":"file.txt","reason":"it would be empty"}` + "\n" +
+		`{"error":"some error","index":2,"kind":"generator_finished"}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
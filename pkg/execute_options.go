@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"os"
+)
+
+// ExecuteOption represents a function that can be given when calling ExecuteTemplate to add specific behaviors.
+type ExecuteOption func(o executeOptions) executeOptions
+
+// ExecuteContext returns an ExecuteOption which attaches input context to the events emitted
+// during ExecuteTemplate (see WithEventSink), i.e. the context given to a Generator,
+// for events to be associated to the right generator.
+func ExecuteContext(ctx context.Context) ExecuteOption {
+	return func(o executeOptions) executeOptions {
+		o.ctx = ctx
+		return o
+	}
+}
+
+// ExecuteEmptyFunc returns an ExecuteOption which overrides the EmptyFunc
+// used to detect whether the executed template is empty (see EmptyFuncFor for the default one).
+func ExecuteEmptyFunc(empty EmptyFunc) ExecuteOption {
+	return func(o executeOptions) executeOptions {
+		o.empty = empty
+		return o
+	}
+}
+
+// ExecuteFormat returns an ExecuteOption which formats the executed template
+// (line endings, byte order mark and final newline) before writing it (see Format.Apply).
+func ExecuteFormat(format Format) ExecuteOption {
+	return func(o executeOptions) executeOptions {
+		o.format = format
+		return o
+	}
+}
+
+// ExecuteNotice returns an ExecuteOption which injects the generated notice of input generator
+// at the top of the executed template (see InjectNotice).
+//
+// An empty generator doesn't inject anything.
+func ExecuteNotice(generator string) ExecuteOption {
+	return func(o executeOptions) executeOptions {
+		o.notice = generator
+		return o
+	}
+}
+
+// ExecuteRoot returns an ExecuteOption which confines all writes, chmods, mkdirs and removals to input root.
+//
+// The out path given to ExecuteTemplate must then be inside root.Name(), an EscapeError is returned otherwise.
+func ExecuteRoot(root *os.Root) ExecuteOption {
+	return func(o executeOptions) executeOptions {
+		o.root = root
+		return o
+	}
+}
+
+// executeOptions represents the struct with all available options in ExecuteTemplate function.
+type executeOptions struct {
+	ctx    context.Context
+	empty  EmptyFunc
+	format Format
+	notice string
+	root   *os.Root
+}
+
+// newExecuteOptions creates a new option struct with all input ExecuteOption functions.
+func newExecuteOptions(opts ...ExecuteOption) executeOptions {
+	var eo executeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			eo = opt(eo)
+		}
+	}
+	return eo
+}
@@ -0,0 +1,398 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/huh/v2"
+)
+
+// FormGroupsFromTags returns the FormGroup functions deriving huh groups from T exported fields and their struct tags.
+// Returned groups can be mixed with hand-written ones in WithFormGroups.
+//
+// Nested structs fields (embedded or not) are walked recursively. Available struct tags are:
+//   - form: the input kind, one of "text", "select", "multiselect" or "confirm" ("-" to skip the field).
+//     By default, it's "confirm" for a bool, "multiselect" for a string slice,
+//     "select" for a string with options and "text" for other strings and numbers
+//   - title: the field title (default field name)
+//   - description: the field description
+//   - options: the comma separated options of a select or multiselect
+//   - required: "true" to return ErrRequiredField when a text or multiselect field is left empty
+//     (a select always has one of its options selected)
+//   - pattern: a regular expression a text field must match
+//   - page: the name of the page (a huh group) the field is part of, pages are ordered by their first field
+//   - hide: the condition hiding the field, referencing another field with its dotted path (e.g. "Maintainer.Name"),
+//     "Field" hides it when Field isn't its zero value, "!Field" when it is,
+//     "Field==value" and "Field!=value" compare Field formatted value
+//
+// Since huh can only hide whole groups, a field with a hide condition is always alone in its own page.
+//
+// Example:
+//
+//	type config struct {
+//		Name    string   `title:"Project name" required:"true" pattern:"^[a-z][a-z0-9-]*$"`
+//		License bool     `title:"Would you like a license ?"`
+//		Kind    string   `title:"Which license ?" options:"MIT,Apache-2.0" hide:"!License"`
+//		CI      []string `options:"github,gitlab" page:"Continuous integration"`
+//		Secrets string   `form:"-"`
+//	}
+func FormGroupsFromTags[T any]() ([]FormGroup[T], error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form groups from tags: '%s' isn't a struct", typ)
+	}
+
+	fields, err := parseTagFields(typ, typ, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("form groups from tags: %w", err)
+	}
+
+	// gather fields by page, in order of appearance
+	var pages []tagPage
+	for _, field := range fields {
+		index := slices.IndexFunc(pages, func(page tagPage) bool { return field.hide == nil && page.hide == nil && page.title == field.page })
+		if index < 0 {
+			pages = append(pages, tagPage{hide: field.hide, title: field.page})
+			index = len(pages) - 1
+		}
+		pages[index].fields = append(pages[index].fields, field)
+	}
+
+	groups := make([]FormGroup[T], 0, len(pages))
+	for _, page := range pages {
+		groups = append(groups, func(config *T) *huh.Group {
+			value := reflect.ValueOf(config).Elem()
+
+			fields := make([]huh.Field, 0, len(page.fields))
+			for _, field := range page.fields {
+				fields = append(fields, field.build(value.FieldByIndex(field.index)))
+			}
+
+			group := huh.NewGroup(fields...).Title(page.title)
+			if page.hide != nil {
+				group = group.WithHideFunc(func() bool { return page.hide.eval(value) })
+			}
+			return group
+		})
+	}
+	return groups, nil
+}
+
+// tagPage represents a page (a huh group) derived from struct tags.
+type tagPage struct {
+	fields []tagField
+	hide   *hideCondition
+	title  string
+}
+
+// tagField represents a form field derived from a struct field and its tags.
+type tagField struct {
+	description string
+	hide        *hideCondition
+	index       []int
+	key         string
+	kind        string
+	options     []string
+	page        string
+	pattern     *regexp.Regexp
+	required    bool
+	title       string
+}
+
+// hideCondition represents a parsed hide struct tag.
+type hideCondition struct {
+	index  []int
+	negate bool
+	op     string
+	value  string
+}
+
+const (
+	kindConfirm     = "confirm"
+	kindMultiSelect = "multiselect"
+	kindSelect      = "select"
+	kindText        = "text"
+)
+
+// parseTagFields returns the form fields of input struct type, prefixing their keys with input prefix.
+//
+// root is the configuration type, used to resolve hide conditions references.
+func parseTagFields(root, typ reflect.Type, index []int, prefix string) ([]tagField, error) {
+	var fields []tagField
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		if !sf.IsExported() || sf.Tag.Get("form") == "-" {
+			continue
+		}
+
+		field := tagField{
+			description: sf.Tag.Get("description"),
+			index:       append(slices.Clone(index), i),
+			key:         prefix + sf.Name,
+			kind:        sf.Tag.Get("form"),
+			page:        sf.Tag.Get("page"),
+			required:    sf.Tag.Get("required") == "true",
+			title:       sf.Tag.Get("title"),
+		}
+		if field.title == "" {
+			field.title = sf.Name
+		}
+
+		if sf.Type.Kind() == reflect.Struct && field.kind == "" {
+			nested, err := parseTagFields(root, sf.Type, field.index, field.key+".")
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+
+		if options := sf.Tag.Get("options"); options != "" {
+			field.options = strings.Split(options, ",")
+		}
+		if pattern := sf.Tag.Get("pattern"); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("field '%s' pattern: %w", field.key, err)
+			}
+			field.pattern = re
+		}
+		if hide := sf.Tag.Get("hide"); hide != "" {
+			condition, err := parseHideCondition(root, hide)
+			if err != nil {
+				return nil, fmt.Errorf("field '%s' hide: %w", field.key, err)
+			}
+			field.hide = &condition
+		}
+
+		if err := field.validate(sf.Type); err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.key, err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// validate infers (if not given) the field kind and checks its tags against the field type.
+func (f *tagField) validate(typ reflect.Type) error {
+	if f.kind == "" {
+		switch {
+		case typ.Kind() == reflect.Bool:
+			f.kind = kindConfirm
+		case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String:
+			f.kind = kindMultiSelect
+		case typ.Kind() == reflect.String && len(f.options) > 0:
+			f.kind = kindSelect
+		default:
+			f.kind = kindText
+		}
+	}
+
+	var valid bool
+	switch f.kind {
+	case kindConfirm:
+		valid = typ.Kind() == reflect.Bool
+	case kindMultiSelect:
+		valid = typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String
+	case kindSelect:
+		valid = typ.Kind() == reflect.String
+	case kindText:
+		valid = isTextKind(typ.Kind())
+	default:
+		return fmt.Errorf("unknown form kind '%s'", f.kind)
+	}
+	if !valid {
+		return fmt.Errorf("unsupported type '%s' for form kind '%s'", typ, f.kind)
+	}
+
+	switch {
+	case (f.kind == kindSelect || f.kind == kindMultiSelect) && len(f.options) == 0:
+		return fmt.Errorf("missing options for form kind '%s'", f.kind)
+	case f.kind != kindSelect && f.kind != kindMultiSelect && len(f.options) > 0:
+		return fmt.Errorf("options aren't supported for form kind '%s'", f.kind)
+	case f.kind != kindText && f.pattern != nil:
+		return fmt.Errorf("pattern isn't supported for form kind '%s'", f.kind)
+	case (f.kind == kindConfirm || f.kind == kindSelect) && f.required:
+		return fmt.Errorf("required isn't supported for form kind '%s'", f.kind)
+	}
+	return nil
+}
+
+// isTextKind returns true when a text input can be used for input kind.
+func isTextKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// build returns the huh field bound to input struct field value.
+func (f tagField) build(value reflect.Value) huh.Field {
+	switch f.kind {
+	case kindConfirm:
+		return huh.NewConfirm().
+			Key(f.key).
+			Title(f.title).
+			Description(f.description).
+			Accessor(&funcAccessor[bool]{get: value.Bool, set: value.SetBool})
+	case kindMultiSelect:
+		get := func() []string {
+			values := make([]string, 0, value.Len())
+			for i := range value.Len() {
+				values = append(values, value.Index(i).String())
+			}
+			return values
+		}
+		set := func(values []string) {
+			slice := reflect.MakeSlice(value.Type(), len(values), len(values))
+			for i, v := range values {
+				slice.Index(i).SetString(v)
+			}
+			value.Set(slice)
+		}
+		return huh.NewMultiSelect[string]().
+			Key(f.key).
+			Title(f.title).
+			Description(f.description).
+			Options(huh.NewOptions(f.options...)...).
+			Accessor(&funcAccessor[[]string]{get: get, set: set}).
+			Validate(func(values []string) error {
+				if f.required && len(values) == 0 {
+					return ErrRequiredField
+				}
+				return nil
+			})
+	case kindSelect:
+		return huh.NewSelect[string]().
+			Key(f.key).
+			Title(f.title).
+			Description(f.description).
+			Options(huh.NewOptions(f.options...)...).
+			Accessor(&funcAccessor[string]{get: value.String, set: value.SetString})
+	default:
+		return huh.NewInput().
+			Key(f.key).
+			Title(f.title).
+			Description(f.description).
+			Accessor(&funcAccessor[string]{get: func() string { return formatValue(value) }, set: func(s string) { _ = parseValue(value, s) }}).
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					if f.required {
+						return ErrRequiredField
+					}
+					return nil
+				}
+				if f.pattern != nil && !f.pattern.MatchString(s) {
+					return fmt.Errorf("must match '%s'", f.pattern)
+				}
+				return parseValue(reflect.New(value.Type()).Elem(), s)
+			})
+	}
+}
+
+// formatValue returns the text representation of input string or number value.
+func formatValue(value reflect.Value) string {
+	if value.Kind() == reflect.String || !value.IsZero() {
+		return fmt.Sprint(value.Interface())
+	}
+	return "" // zero numbers are displayed empty
+}
+
+// parseValue sets input string or number value from its text representation.
+func parseValue(value reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, value.Type().Bits())
+		if err != nil && s != "" {
+			return errors.New("must be an integer")
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, value.Type().Bits())
+		if err != nil && s != "" {
+			return errors.New("must be a positive integer")
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, value.Type().Bits())
+		if err != nil && s != "" {
+			return errors.New("must be a number")
+		}
+		value.SetFloat(f)
+	}
+	return nil
+}
+
+// funcAccessor is a huh.Accessor getting and setting its value with functions.
+type funcAccessor[V any] struct {
+	get func() V
+	set func(V)
+}
+
+var _ huh.Accessor[bool] = (*funcAccessor[bool])(nil) // ensure interface is implemented
+
+// Get implements huh.Accessor.
+func (a *funcAccessor[V]) Get() V {
+	return a.get()
+}
+
+// Set implements huh.Accessor.
+func (a *funcAccessor[V]) Set(value V) {
+	a.set(value)
+}
+
+// parseHideCondition parses input hide tag, resolving its field reference against root type.
+func parseHideCondition(root reflect.Type, hide string) (hideCondition, error) {
+	var condition hideCondition
+	path := hide
+	for _, op := range []string{"==", "!="} {
+		if name, value, ok := strings.Cut(hide, op); ok {
+			path, condition.op, condition.value = name, op, value
+			break
+		}
+	}
+	if condition.op == "" {
+		path, condition.negate = strings.CutPrefix(path, "!")
+	}
+
+	typ := root
+	for name := range strings.SplitSeq(strings.TrimSpace(path), ".") {
+		if typ.Kind() != reflect.Struct {
+			return hideCondition{}, fmt.Errorf("unknown field '%s'", path)
+		}
+		sf, ok := typ.FieldByName(name)
+		if !ok || !sf.IsExported() {
+			return hideCondition{}, fmt.Errorf("unknown field '%s'", path)
+		}
+		condition.index = append(condition.index, sf.Index...)
+		typ = sf.Type
+	}
+	return condition, nil
+}
+
+// eval returns true when the condition is met for input configuration value.
+func (c hideCondition) eval(config reflect.Value) bool {
+	value := config.FieldByIndex(c.index)
+	switch c.op {
+	case "==":
+		return fmt.Sprint(value.Interface()) == c.value
+	case "!=":
+		return fmt.Sprint(value.Interface()) != c.value
+	default:
+		return value.IsZero() == c.negate
+	}
+}
@@ -0,0 +1,236 @@
+package engine_test
+
+import (
+	"testing"
+
+	"charm.land/huh/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/testutils"
+)
+
+type tagsMaintainer struct {
+	Name  string `required:"true" title:"Maintainer name"`
+	Email string `pattern:"^.+@.+$"`
+}
+
+type tagsConfig struct {
+	Maintainer tagsMaintainer
+	License    bool
+	Owner      string   `hide:"!License" required:"true" title:"License owner"`
+	Kind       string   `options:"MIT,Apache-2.0"`
+	Port       int      `page:"Server"`
+	CI         []string `options:"github,gitlab" page:"Server"`
+	Secret     string   `form:"-"`
+	internal   string
+}
+
+func TestFormGroupsFromTags(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			groups   func() error
+			expected string
+		}{
+			{
+				name:     "not_struct",
+				groups:   func() error { _, err := engine.FormGroupsFromTags[string](); return err },
+				expected: "'string' isn't a struct",
+			},
+			{
+				name: "unknown_kind",
+				groups: func() error {
+					_, err := engine.FormGroupsFromTags[struct {
+						Field string `form:"slider"`
+					}]()
+					return err
+				},
+				expected: "field 'Field': unknown form kind 'slider'",
+			},
+			{
+				name: "unsupported_type",
+				groups: func() error {
+					_, err := engine.FormGroupsFromTags[struct{ Field map[string]string }]()
+					return err
+				},
+				expected: "field 'Field': unsupported type 'map[string]string' for form kind 'text'",
+			},
+			{
+				name: "missing_options",
+				groups: func() error {
+					_, err := engine.FormGroupsFromTags[struct {
+						Field string `form:"select"`
+					}]()
+					return err
+				},
+				expected: "field 'Field': missing options for form kind 'select'",
+			},
+			{
+				name: "required_select",
+				groups: func() error {
+					_, err := engine.FormGroupsFromTags[struct {
+						Field string `options:"a,b" required:"true"`
+					}]()
+					return err
+				},
+				expected: "field 'Field': required isn't supported for form kind 'select'",
+			},
+			{
+				name: "invalid_pattern",
+				groups: func() error {
+					_, err := engine.FormGroupsFromTags[struct {
+						Field string `pattern:"["`
+					}]()
+					return err
+				},
+				expected: "field 'Field' pattern: error parsing regexp",
+			},
+			{
+				name: "unknown_hide_field",
+				groups: func() error {
+					_, err := engine.FormGroupsFromTags[struct {
+						Field string `hide:"!Other"`
+					}]()
+					return err
+				},
+				expected: "field 'Field' hide: unknown field 'Other'",
+			},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Act
+				err := tc.groups()
+
+				// Assert
+				assert.ErrorContains(t, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("error_invalid_answers", func(t *testing.T) {
+		// Arrange
+		groups, err := engine.FormGroupsFromTags[tagsConfig]()
+		require.NoError(t, err)
+
+		// Act
+		_, err = engine.Initialize(ctx,
+			engine.WithFormGroups(groups...),
+			engine.WithAnswers[tagsConfig](engine.AnswersMap(map[string]any{"maintainer": map[string]any{"email": "invalid"}})),
+			engine.WithInteractive[tagsConfig](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorIs(t, err, engine.ErrInvalidAnswers)
+		assert.ErrorIs(t, err, engine.ErrRequiredField)
+		assert.ErrorContains(t, err, "field 'Maintainer.Name'")
+		assert.ErrorContains(t, err, "field 'Maintainer.Email': must match '^.+@.+$'")
+	})
+
+	t.Run("error_shown_field", func(t *testing.T) {
+		// Arrange
+		groups, err := engine.FormGroupsFromTags[tagsConfig]()
+		require.NoError(t, err)
+
+		answers := map[string]any{"maintainer": map[string]any{"name": "jane"}, "license": true}
+
+		// Act
+		_, err = engine.Initialize(ctx,
+			engine.WithFormGroups(groups...),
+			engine.WithAnswers[tagsConfig](engine.AnswersMap(answers)),
+			engine.WithInteractive[tagsConfig](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorIs(t, err, engine.ErrRequiredField)
+		assert.ErrorContains(t, err, "field 'Owner'")
+	})
+
+	t.Run("success_answers", func(t *testing.T) {
+		// Arrange
+		groups, err := engine.FormGroupsFromTags[tagsConfig]()
+		require.NoError(t, err)
+
+		answers := map[string]any{
+			"maintainer": map[string]any{"name": "jane", "email": "jane@example.com"},
+			"port":       "8080",
+			"ci":         []string{"gitlab"},
+		}
+		expected := tagsConfig{
+			Maintainer: tagsMaintainer{Name: "jane", Email: "jane@example.com"},
+			Kind:       "MIT",
+			Port:       8080,
+			CI:         []string{"gitlab"},
+		}
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithFormGroups(groups...),
+			engine.WithAnswers[tagsConfig](engine.AnswersMap(answers)),
+			engine.WithInteractive[tagsConfig](engine.InteractiveNever))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("error_mixed", func(t *testing.T) {
+		// Arrange
+		type config struct {
+			Name string `required:"true"`
+			Str  string `form:"-"`
+		}
+		groups, err := engine.FormGroupsFromTags[config]()
+		require.NoError(t, err)
+		custom := func(c *config) *huh.Group {
+			return huh.NewGroup(huh.NewInput().Key("str").Value(&c.Str).Validate(func(s string) error {
+				if s == "" {
+					return engine.ErrRequiredField
+				}
+				return nil
+			}))
+		}
+
+		// Act
+		_, err = engine.Initialize(ctx,
+			engine.WithFormGroups(append(groups, custom)...),
+			engine.WithAnswers[config](engine.AnswersMap(map[string]any{"name": "name"})),
+			engine.WithInteractive[config](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorIs(t, err, engine.ErrRequiredField)
+		assert.ErrorContains(t, err, "field 'str'")
+	})
+
+	t.Run("success_interactive", func(t *testing.T) {
+		// Arrange
+		type config struct {
+			Name    string `pattern:"^[a-z]+$" required:"true"`
+			Port    uint
+			License bool
+			Kind    string   `options:"MIT,Apache-2.0"`
+			CI      []string `options:"github,gitlab"`
+		}
+		groups, err := engine.FormGroupsFromTags[config]()
+		require.NoError(t, err)
+
+		scripts := []testutils.FormScript{testutils.Script(
+			"Name", testutils.KeyEnter, // invalid pattern
+			testutils.KeyBackspace, testutils.KeyBackspace, testutils.KeyBackspace, testutils.KeyBackspace, "name", testutils.KeyEnter,
+			"42", testutils.KeyEnter,
+			testutils.KeyLeft, testutils.KeyEnter, // yes
+			testutils.KeyDown, testutils.KeyEnter, // Apache-2.0
+			testutils.KeyDown, testutils.KeySpace, testutils.KeyEnter, // gitlab
+		)}
+
+		// Act
+		actual, errs, err := testutils.Initialize(t, scripts, engine.WithFormGroups(groups...))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, config{Name: "name", Port: 42, License: true, Kind: "Apache-2.0", CI: []string{"gitlab"}}, actual)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "must match '^[a-z]+$'")
+	})
+}
@@ -0,0 +1,103 @@
+package engine
+
+import "bytes"
+
+// LineEnding defines the line endings of a generated file.
+//
+// By default, line endings are kept as they are generated.
+type LineEnding int
+
+const (
+	// LineEndingLF converts all line endings to LF ("\n").
+	LineEndingLF LineEnding = iota + 1
+
+	// LineEndingCRLF converts all line endings to CRLF ("\r\n"),
+	// e.g. for Windows .bat or .ps1 files.
+	LineEndingCRLF
+)
+
+// FinalNewline defines the final newline handling of a generated file.
+//
+// By default, the final newline is kept as it is generated.
+type FinalNewline int
+
+const (
+	// FinalNewlineAdd ensures the generated file ends with exactly one newline.
+	FinalNewlineAdd FinalNewline = iota + 1
+
+	// FinalNewlineRemove ensures the generated file doesn't end with any newline.
+	FinalNewlineRemove
+)
+
+var (
+	bom  = []byte{0xEF, 0xBB, 0xBF}
+	crlf = []byte("\r\n")
+	lf   = []byte("\n")
+)
+
+// Format represents the output format of a generated file (line endings, byte order mark and final newline).
+//
+// Its zero value keeps the generated content as is.
+type Format struct {
+	// BOM adds the UTF-8 byte order mark at the start of the generated file.
+	//
+	// An already present byte order mark is always kept.
+	BOM bool
+
+	// FinalNewline is the final newline handling of the generated file.
+	FinalNewline FinalNewline
+
+	// LineEnding is the line endings of the generated file.
+	LineEnding LineEnding
+}
+
+// Apply returns the input content formatted according to the current format.
+func (f Format) Apply(content []byte) []byte {
+	if f == (Format{}) {
+		return content
+	}
+
+	detected, normalized := normalize(content)
+	f = f.merge(detected)
+
+	switch f.FinalNewline {
+	case FinalNewlineAdd:
+		normalized = append(bytes.TrimRight(normalized, "\n"), '\n')
+	case FinalNewlineRemove:
+		normalized = bytes.TrimRight(normalized, "\n")
+	default:
+	}
+
+	if f.LineEnding == LineEndingCRLF {
+		normalized = bytes.ReplaceAll(normalized, lf, crlf)
+	}
+	if f.BOM {
+		normalized = append(bytes.Clone(bom), normalized...)
+	}
+	return normalized
+}
+
+// merge returns the current format completed with the input detected format,
+// i.e. detected line endings are kept when none are specified and a detected byte order mark is always kept.
+func (f Format) merge(detected Format) Format {
+	if f.LineEnding == 0 {
+		f.LineEnding = detected.LineEnding
+	}
+	f.BOM = f.BOM || detected.BOM
+	return f
+}
+
+// normalize returns the input content without byte order mark and with LF line endings,
+// alongside the detected format of content (byte order mark presence and CRLF line endings).
+func normalize(content []byte) (Format, []byte) {
+	var detected Format
+	if bytes.HasPrefix(content, bom) {
+		detected.BOM = true
+		content = content[len(bom):]
+	}
+	if bytes.Contains(content, crlf) {
+		detected.LineEnding = LineEndingCRLF
+		content = bytes.ReplaceAll(content, crlf, lf)
+	}
+	return detected, content
+}
@@ -0,0 +1,42 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	engine "github.com/kickr-dev/engine/pkg"
+)
+
+func TestFormatApply(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   engine.Format
+		content  string
+		expected string
+	}{
+		{"zero", engine.Format{}, "line\r\nline", "line\r\nline"},
+		{"crlf", engine.Format{LineEnding: engine.LineEndingCRLF}, "line\nline\r\n", "line\r\nline\r\n"},
+		{"lf", engine.Format{LineEnding: engine.LineEndingLF}, "line\r\nline\r\n", "line\nline\n"},
+		{"bom", engine.Format{BOM: true}, "line\n", "\xEF\xBB\xBFline\n"},
+		{"bom_already_present", engine.Format{BOM: true}, "\xEF\xBB\xBFline\n", "\xEF\xBB\xBFline\n"},
+		{"final_newline_add", engine.Format{FinalNewline: engine.FinalNewlineAdd}, "line\n\n\n", "line\n"},
+		{"final_newline_add_missing", engine.Format{FinalNewline: engine.FinalNewlineAdd}, "line", "line\n"},
+		{"final_newline_remove", engine.Format{FinalNewline: engine.FinalNewlineRemove}, "line\n\n", "line"},
+		{"final_newline_remove_crlf", engine.Format{FinalNewline: engine.FinalNewlineRemove}, "line\r\nline\r\n", "line\r\nline"},
+		{
+			"all", engine.Format{BOM: true, FinalNewline: engine.FinalNewlineAdd, LineEnding: engine.LineEndingCRLF},
+			"@echo off\necho hi", "\xEF\xBB\xBF@echo off\r\necho hi\r\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			content := tc.format.Apply([]byte(tc.content))
+
+			// Assert
+			assert.Equal(t, tc.expected, string(content))
+		})
+	}
+}
@@ -3,8 +3,10 @@ package engine
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"runtime"
 	"sync/atomic"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -19,11 +21,17 @@ var ErrFailedGeneration = errors.New("some error(s) occurred during generation")
 //
 // It executes all parsers given in options (or default ones), in order,
 // and then runs all provided generators concurrently (bounded to runtime.GOMAXPROCS(0)) to apply or remove templates.
+//
+// Each step is reported to the EventSink configured with WithEventSink, if any.
 func Generate[T any](ctx context.Context, destdir string, config T, parsers []Parser[T], generators []Generator[T]) error {
 	// parse repository
 	errs := make([]error, 0, len(parsers))
-	for _, parser := range parsers {
-		errs = append(errs, parser(ctx, destdir, &config))
+	for index, parser := range parsers {
+		ctx := withIndex(ctx, index)
+		emit(ctx, Event{Kind: EventParserStarted})
+		err := parser(ctx, destdir, &config)
+		emit(ctx, Event{Kind: EventParserFinished, Err: err})
+		errs = append(errs, err)
 	}
 	if err := errors.Join(errs...); err != nil {
 		return err
@@ -34,11 +42,17 @@ func Generate[T any](ctx context.Context, destdir string, config T, parsers []Pa
 	group.SetLimit(runtime.GOMAXPROCS(0))
 
 	var failed atomic.Bool
-	for _, generator := range generators {
+	for index, generator := range generators {
 		group.Go(func() error {
-			if err := generator(ctx, destdir, config); err != nil {
+			ctx := withIndex(ctx, index)
+			emit(ctx, Event{Kind: EventGeneratorStarted})
+			start := time.Now()
+			err := generator(ctx, destdir, config)
+			emit(ctx, Event{Kind: EventGeneratorFinished, Err: err})
+			if err != nil {
 				if !errors.Is(err, ErrFailedGeneration) {
-					GetLogger().Errorf("%s", err.Error())
+					attrs := []slog.Attr{slog.Int("generator", index), slog.Duration("duration", time.Since(start))}
+					logAttrs(ctx, slog.LevelError, attrs, "%s", err.Error())
 				}
 				failed.Store(true)
 			}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"regexp"
 	"sync/atomic"
 	"text/template"
 )
@@ -29,6 +30,38 @@ func WithForce(force bool) OptionFunc {
 	}
 }
 
+// WithChecksums sets the generated files checksums store when calling Configure with this option.
+//
+// Checksums are updated whenever a file is written or removed during generation
+// and are used to evaluate PolicyHash (see ShouldGenerate).
+// Checksums.Write must be called once generation is done to persist them.
+func WithChecksums(checksums *Checksums) OptionFunc {
+	return func(o options) options {
+		o.checksums = checksums
+		return o
+	}
+}
+
+// WithEmptyFuncs sets the EmptyFunc to use per file extension (e.g. ".yml") or file name (e.g. "Dockerfile")
+// when calling Configure with this option.
+//
+// They take precedence over built-in EmptyFunc (see EmptyFuncFor).
+func WithEmptyFuncs(funcs map[string]EmptyFunc) OptionFunc {
+	return func(o options) options {
+		o.emptyFuncs = funcs
+		return o
+	}
+}
+
+// WithEventSink sets the EventSink receiving all events emitted during Generate when calling Configure with this option,
+// e.g. to drive a progress view (see progress package) or a JSON lines stream (see NewJSONEventSink).
+func WithEventSink(sink EventSink) OptionFunc {
+	return func(o options) options {
+		o.sink = sink
+		return o
+	}
+}
+
 // WithFuncMap adds a custom FuncMap to all templating calls.
 func WithFuncMap(funcs template.FuncMap) OptionFunc {
 	return func(o options) options {
@@ -37,6 +70,20 @@ func WithFuncMap(funcs template.FuncMap) OptionFunc {
 	}
 }
 
+// WithGeneratedNotices sets the regexps used to detect generated notices when calling Configure with this option.
+//
+// Each regexp should capture the generator name, either in a submatch named "generator" or in its first submatch,
+// for GeneratedBy to report it, e.g. "Code generated by (?P<generator>[\w\-\/\.]+); DO NOT EDIT.".
+//
+// Without this option (or with no regexps), the default notice "Code generated by ([\w\-\/\.]+); DO NOT EDIT." is used.
+// Give it again alongside custom regexps to keep detecting it.
+func WithGeneratedNotices(notices ...*regexp.Regexp) OptionFunc {
+	return func(o options) options {
+		o.notices = notices
+		return o
+	}
+}
+
 // GetLogger returns global logger if it exists or a noop logger.
 func GetLogger() Logger {
 	opts := o.Load()
@@ -54,6 +101,33 @@ func Forced() bool {
 	return opts != nil && opts.force
 }
 
+// checksums returns the configured checksums store, if any.
+func checksums() *Checksums {
+	opts := o.Load()
+	if opts == nil {
+		return nil
+	}
+	return opts.checksums
+}
+
+// customEmptyFuncs returns the configured EmptyFunc, if any.
+func customEmptyFuncs() map[string]EmptyFunc {
+	opts := o.Load()
+	if opts == nil {
+		return nil
+	}
+	return opts.emptyFuncs
+}
+
+// eventSink returns the configured EventSink, if any.
+func eventSink() EventSink {
+	opts := o.Load()
+	if opts == nil {
+		return nil
+	}
+	return opts.sink
+}
+
 // funcs returns the configured custom FuncMap, if any.
 func funcs() template.FuncMap {
 	opts := o.Load()
@@ -63,6 +137,24 @@ func funcs() template.FuncMap {
 	return opts.funcs
 }
 
+// notices returns the configured generated notices regexps or the default one.
+func notices() []*regexp.Regexp {
+	opts := o.Load()
+	if opts == nil || len(opts.notices) == 0 {
+		return []*regexp.Regexp{generated}
+	}
+	return opts.notices
+}
+
+// noticeLines returns the configured generated notices lines regexps or the default one.
+func noticeLines() []*regexp.Regexp {
+	opts := o.Load()
+	if opts == nil || len(opts.notices) == 0 {
+		return []*regexp.Regexp{generatedLine}
+	}
+	return opts.noticeLines
+}
+
 // Configure applies the options functions to the global option variable (unexported).
 //
 // This function should be called before calling any function within engine package in case a specific logger must be set
@@ -78,13 +170,21 @@ func Configure(opts ...OptionFunc) {
 	if next.logger == nil {
 		next.logger = &noopLogger{}
 	}
+	for _, notice := range next.notices {
+		next.noticeLines = append(next.noticeLines, noticeLine(notice))
+	}
 	o.Store(&next)
 }
 
 var o atomic.Pointer[options]
 
 type options struct {
-	force  bool
-	funcs  template.FuncMap
-	logger Logger
+	checksums   *Checksums
+	emptyFuncs  map[string]EmptyFunc
+	force       bool
+	funcs       template.FuncMap
+	logger      Logger
+	noticeLines []*regexp.Regexp
+	notices     []*regexp.Regexp
+	sink        EventSink
 }
@@ -11,7 +11,7 @@ import (
 //
 // By default, the policy is set to PolicyNone,
 // meaning that a given file will be generated if it doesn't exist, is empty
-// or if the notice "Code generated by [\w\-\/]+; DO NOT EDIT." is present.
+// or if a generated notice (see GeneratedBy) is present.
 type GeneratePolicy int
 
 const (
@@ -21,11 +21,86 @@ const (
 	// PolicyNone will generate the file using the default behavior.
 	//
 	// It means that a given file will be generated if it doesn't exist, is empty
-	// or if the notice "Code generated by [\w\-\/]+; DO NOT EDIT." is present.
+	// or if a generated notice (see GeneratedBy) is present.
 	PolicyNone
+
+	// PolicyCreateOnce generates the file only if it doesn't exist,
+	// even when it's empty or contains a generated notice.
+	PolicyCreateOnce
+
+	// PolicyNever never generates the file, only its patches are applied
+	// (or the file is removed according to Template.Remove).
+	//
+	// It isn't affected by forced generation (see WithForce).
+	PolicyNever
+
+	// PolicyHash generates the file only if it doesn't exist
+	// or if its content is still the same as the last time the engine wrote it,
+	// according to the checksums provided with WithChecksums option.
+	//
+	// When no checksum is known for the file, it falls back to PolicyNone behavior.
+	PolicyHash
 )
 
-var generated = regexp.MustCompile(`Code generated by [\w\-\/]+; DO NOT EDIT.`)
+// String returns the snake case representation of the GeneratePolicy, e.g. "create_once".
+func (p GeneratePolicy) String() string {
+	switch p {
+	case PolicyAlways:
+		return "always"
+	case PolicyNone:
+		return "none"
+	case PolicyCreateOnce:
+		return "create_once"
+	case PolicyNever:
+		return "never"
+	case PolicyHash:
+		return "hash"
+	default:
+		return "default"
+	}
+}
+
+// generated is the default generated notice regexp, capturing the generator name.
+var generated = regexp.MustCompile(`Code generated by ([\w\-\/\.]+); DO NOT EDIT.`)
+
+// GeneratedBy returns the name of the generator named in content generated notice.
+//
+// Generated notices are matched with the regexps provided with WithGeneratedNotices option (in order)
+// or by default with "Code generated by ([\w\-\/\.]+); DO NOT EDIT.".
+// The generator name is the submatch named "generator" when it exists or the first submatch otherwise.
+//
+// It returns false when no generated notice is present in content,
+// and an empty name when the matching regexp doesn't capture any generator name.
+func GeneratedBy(content []byte) (string, bool) {
+	for _, notice := range notices() {
+		match := notice.FindSubmatch(content)
+		if match == nil {
+			continue
+		}
+		if index := notice.SubexpIndex("generator"); index > 0 {
+			return string(match[index]), true
+		}
+		if len(match) > 1 {
+			return string(match[1]), true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// foreignGenerator returns the name of the generator named in out generated notice
+// when it's different from the input generator.
+//
+// It returns false when out can't be read, has no generated notice
+// or when the generated notice doesn't name any generator.
+func foreignGenerator(out, generator string) (string, bool) {
+	content, err := os.ReadFile(out)
+	if err != nil {
+		return "", false
+	}
+	owner, ok := GeneratedBy(content)
+	return owner, ok && owner != "" && owner != generator
+}
 
 // ShouldGenerate returns true if the file should be generated.
 //
@@ -34,19 +109,48 @@ var generated = regexp.MustCompile(`Code generated by [\w\-\/]+; DO NOT EDIT.`)
 //   - it does not exist
 //   - it is empty
 //   - the policy is set to PolicyAlways
+//
+// With PolicyCreateOnce, the file is generated only if it doesn't exist.
+// With PolicyHash, the file is generated only if it doesn't exist or is unchanged since its last generation.
+// With PolicyNever, the file is never generated.
+//
+// An existing symbolic link is never generated (unless with PolicyAlways or forced generation),
+// since it's considered as manually managed and generating it would write through it.
 func ShouldGenerate(out string, policy GeneratePolicy) (bool, error) {
+	if policy == PolicyNever {
+		return false, nil
+	}
 	if policy == PolicyAlways || Forced() {
 		return true, nil
 	}
 
-	content, err := os.ReadFile(out)
+	info, err := os.Lstat(out)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return true, nil
 		}
 		return false, err
 	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return false, nil // symbolic links are considered as manually managed
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		return false, err
+	}
+
+	switch policy {
+	case PolicyCreateOnce:
+		return false, nil
+	case PolicyHash:
+		if sum, ok := checksums().Sum(out); ok {
+			return sum == checksum(content), nil
+		}
+	default:
+	}
 
 	// no reuse of IsEmpty: ShouldGenerate regenerates as soon as the notice appears anywhere in the file
-	return len(content) == 0 || generated.Match(content), nil
+	_, ok := GeneratedBy(content)
+	return len(content) == 0 || ok, nil
 }
@@ -3,6 +3,7 @@ package engine_test
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,6 +45,70 @@ func TestShouldGenerate(t *testing.T) {
 		assert.True(t, ok)
 	})
 
+	t.Run("never", func(t *testing.T) {
+		// Arrange
+		engine.Configure(engine.WithForce(true))
+		t.Cleanup(func() { engine.Configure(engine.WithForce(false)) })
+
+		dest := filepath.Join(t.TempDir(), "invalid.txt")
+
+		// Act
+		ok, err := engine.ShouldGenerate(dest, engine.PolicyNever)
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("create_once", func(t *testing.T) {
+		// Arrange
+		dest := filepath.Join(t.TempDir(), "file.txt")
+
+		// Act
+		missing, err := engine.ShouldGenerate(dest, engine.PolicyCreateOnce)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(dest, []byte("# Code generated by kickr; DO NOT EDIT."), files.RwRR))
+		existing, err := engine.ShouldGenerate(dest, engine.PolicyCreateOnce)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, missing)
+		assert.False(t, existing)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		checksums, err := engine.ReadChecksums(filepath.Join(destdir, "checksums.json"))
+		require.NoError(t, err)
+		engine.Configure(engine.WithChecksums(checksums))
+		t.Cleanup(func() { engine.Configure() })
+
+		unchanged := filepath.Join(destdir, "unchanged.txt")
+		require.NoError(t, os.WriteFile(unchanged, []byte("generated"), files.RwRR))
+		checksums.Set(unchanged, []byte("generated"))
+
+		changed := filepath.Join(destdir, "changed.txt")
+		require.NoError(t, os.WriteFile(changed, []byte("# Code generated by kickr; DO NOT EDIT.\nmodified"), files.RwRR))
+		checksums.Set(changed, []byte("# Code generated by kickr; DO NOT EDIT.\n"))
+
+		unknown := filepath.Join(destdir, "unknown.txt")
+		require.NoError(t, os.WriteFile(unknown, []byte("# Code generated by kickr; DO NOT EDIT."), files.RwRR))
+
+		// Act
+		unchangedOK, err := engine.ShouldGenerate(unchanged, engine.PolicyHash)
+		require.NoError(t, err)
+		changedOK, err := engine.ShouldGenerate(changed, engine.PolicyHash)
+		require.NoError(t, err)
+		unknownOK, err := engine.ShouldGenerate(unknown, engine.PolicyHash)
+		require.NoError(t, err)
+
+		// Assert
+		assert.True(t, unchangedOK)
+		assert.False(t, changedOK)
+		assert.True(t, unknownOK)
+	})
+
 	t.Run("generated_doesnt_exist", func(t *testing.T) {
 		// Arrange
 		dest := filepath.Join(t.TempDir(), "invalid.txt")
@@ -155,3 +220,71 @@ func TestShouldGenerate(t *testing.T) {
 		assert.True(t, ok)
 	})
 }
+
+func TestGeneratePolicyString(t *testing.T) {
+	cases := []struct {
+		policy   engine.GeneratePolicy
+		expected string
+	}{
+		{policy: 0, expected: "default"},
+		{policy: engine.PolicyAlways, expected: "always"},
+		{policy: engine.PolicyNone, expected: "none"},
+		{policy: engine.PolicyCreateOnce, expected: "create_once"},
+		{policy: engine.PolicyNever, expected: "never"},
+		{policy: engine.PolicyHash, expected: "hash"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			// Act
+			actual := tc.policy.String()
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestGeneratedBy(t *testing.T) {
+	t.Run("success_default", func(t *testing.T) {
+		cases := []struct {
+			name      string
+			content   string
+			generator string
+			ok        bool
+		}{
+			{"no_notice", "some content", "", false},
+			{"notice", "# Code generated by kickr; DO NOT EDIT.", "kickr", true},
+			{"notice_with_dots", "// Code generated by kickr.dev/layout; DO NOT EDIT.", "kickr.dev/layout", true},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Act
+				generator, ok := engine.GeneratedBy([]byte(tc.content))
+
+				// Assert
+				assert.Equal(t, tc.ok, ok)
+				assert.Equal(t, tc.generator, generator)
+			})
+		}
+	})
+
+	t.Run("success_configured", func(t *testing.T) {
+		// Arrange
+		engine.Configure(engine.WithGeneratedNotices(
+			regexp.MustCompile(`@generated by (?P<generator>\S+)`),
+			regexp.MustCompile(`DO NOT EDIT`)))
+		t.Cleanup(func() { engine.Configure() })
+
+		// Act
+		named, namedOK := engine.GeneratedBy([]byte("# @generated by protoc"))
+		anonymous, anonymousOK := engine.GeneratedBy([]byte("# DO NOT EDIT"))
+		_, defaultOK := engine.GeneratedBy([]byte("# Code generated by kickr; DO NOT"))
+
+		// Assert
+		assert.True(t, namedOK)
+		assert.Equal(t, "protoc", named)
+		assert.True(t, anonymousOK)
+		assert.Empty(t, anonymous)
+		assert.False(t, defaultOK)
+	})
+}
@@ -3,19 +3,60 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"slices"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/huh/v2"
+	"github.com/charmbracelet/x/term"
 )
 
 // Initialize initializes a new project an returns resulting configuration.
 //
 // All user inputs are configured through WithFormGroups option, by default the main maintainer
 // and chart generation will be asked.
+//
+// Parsers given with WithParsers are run first (in order, on WithDestdir directory) to detect values
+// from an existing repository, as such FormGroup functions receive a pre-populated configuration.
+//
+// Answers given with WithAnswers are then decoded into the configuration before the form is run,
+// as such they're the default values of the form fields.
+// In non-interactive mode (see WithInteractive), the form isn't run,
+// each field validation is run against the answers instead and ErrInvalidAnswers is returned
+// with the list of missing or invalid answers.
+//
+// A review page can be shown at the end of the interactive form with WithReview.
+// Answers can be recorded after a successful run with WithRecord and replayed later with WithReplay.
 func Initialize[T any](ctx context.Context, opts ...InitializeOption[T]) (T, error) {
 	ro := newInitializeOpt(opts...)
 
 	var config T
+	for _, parser := range ro.parsers {
+		if err := parser(ctx, ro.destdir, &config); err != nil {
+			return config, err
+		}
+	}
+
+	if ro.record != nil {
+		// fail early instead of after the user answered all questions
+		if _, err := answersFormatFor(ro.record.src); err != nil {
+			return config, err
+		}
+	}
+
+	sources, interactive, err := ro.replayAnswers()
+	if err != nil {
+		return config, err
+	}
+	answers, err := readAnswers(sources...)
+	if err != nil {
+		return config, err
+	}
+	if err := decodeAnswers(answers, &config); err != nil {
+		return config, err
+	}
+
 	groups := make([]*huh.Group, 0, len(ro.formGroups))
 	for _, formGroup := range ro.formGroups {
 		if group := formGroup(&config); group != nil {
@@ -23,15 +64,87 @@ func Initialize[T any](ctx context.Context, opts ...InitializeOption[T]) (T, err
 		}
 	}
 
-	form := huh.NewForm(groups...).
-		WithProgramOptions(ro.options...).
-		WithShowErrors(true)
-	return config, form.RunWithContext(ctx)
+	if interactive && ro.runForm() {
+		form := huh.NewForm(groups...).
+			WithProgramOptions(ro.options...).
+			WithShowErrors(true)
+		if err := ro.run(ctx, form); err != nil {
+			return config, err
+		}
+		if ro.review {
+			if err := ro.reviewForm(ctx, &config); err != nil {
+				return config, err
+			}
+		}
+	} else if err := validateForm(groups...); err != nil {
+		return config, err
+	}
+
+	if ro.record != nil {
+		if err := writeRecord(ro.record.src, ro.record.version, config); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+// DetectedOptions returns input select options with the detected value (e.g. a configuration field pre-populated
+// by a parser, see WithParsers) marked with a "(detected)" suffix, or prepended to them when it's not one of them.
+//
+// A zero detected value (nothing was detected) returns input options as is.
+// It can be used with huh.Select Options or within OptionsFunc for options depending on other fields.
+func DetectedOptions[V comparable](detected V, options ...huh.Option[V]) []huh.Option[V] {
+	var zero V
+	if detected == zero {
+		return options
+	}
+
+	result := make([]huh.Option[V], 0, len(options)+1)
+	found := false
+	for _, option := range options {
+		if option.Value == detected {
+			option.Key += " (detected)"
+			found = true
+		}
+		result = append(result, option)
+	}
+	if !found {
+		result = slices.Insert(result, 0, huh.NewOption(fmt.Sprintf("%v (detected)", detected), detected))
+	}
+	return result
 }
 
 // ErrRequiredField is the error that can be used with huh.Validate(f func(string) error) to specify to the user that the field is required.
 var ErrRequiredField = errors.New("required field")
 
+// Interactive defines whether Initialize runs its form interactively.
+type Interactive int
+
+const (
+	// InteractiveAlways always runs the form, it's the default behavior.
+	InteractiveAlways Interactive = iota + 1
+
+	// InteractiveNever never runs the form, the configuration is only filled with answers (see WithAnswers).
+	InteractiveNever
+
+	// InteractiveAuto runs the form only when stdin is a terminal (e.g. not in CI or in a pipe).
+	InteractiveAuto
+)
+
+// String returns the snake case representation of Interactive, e.g. "auto".
+func (i Interactive) String() string {
+	switch i {
+	case InteractiveAlways:
+		return "always"
+	case InteractiveNever:
+		return "never"
+	case InteractiveAuto:
+		return "auto"
+	default:
+		return fmt.Sprintf("Interactive(%d)", int(i))
+	}
+}
+
 // InitializeOption represents an option to be given to Initialize function.
 type InitializeOption[T any] func(initializeOptions[T]) initializeOptions[T]
 
@@ -43,6 +156,17 @@ func WithTeaOptions[T any](opts ...tea.ProgramOption) InitializeOption[T] {
 	}
 }
 
+// WithFormRunner sets the function running Initialize forms (main form and review ones, see WithReview),
+// by default huh.Form RunWithContext.
+//
+// It can be used to drive forms without any terminal, e.g. in tests (see testutils.Initialize).
+func WithFormRunner[T any](runner func(ctx context.Context, form *huh.Form) error) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.runner = runner
+		return ro
+	}
+}
+
 // FormGroup is the signature function for functions reading user inputs.
 // Inspiration can be found with ReadMaintainer and ReadChart functions.
 type FormGroup[T any] func(config *T) *huh.Group
@@ -55,15 +179,62 @@ func WithFormGroups[T any](inputs ...FormGroup[T]) InitializeOption[T] {
 	}
 }
 
+// WithDestdir sets the directory given to parsers in Initialize function (default current directory).
+func WithDestdir[T any](destdir string) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.destdir = destdir
+		return ro
+	}
+}
+
+// WithParsers sets (it overrides the previously defined functions everytime it's called) the parsers
+// run in Initialize function before the form, to pre-populate the configuration with values detected
+// in an existing repository (e.g. its git remote or its go.mod module).
+//
+// Parsers are the same ones as in Generate, as such they must not fail when the repository doesn't exist yet.
+func WithParsers[T any](parsers ...Parser[T]) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.parsers = parsers
+		return ro
+	}
+}
+
+// WithAnswers adds answers sources (see AnswersMap, AnswersFile and AnswersEnv) to fill the configuration in Initialize function.
+//
+// Sources are merged in order, later sources overriding previous ones,
+// it can be called multiple times to add more sources.
+func WithAnswers[T any](sources ...AnswersSource) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.answers = append(ro.answers, sources...)
+		return ro
+	}
+}
+
+// WithInteractive sets whether the form of Initialize function is run interactively (default InteractiveAlways).
+func WithInteractive[T any](interactive Interactive) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.interactive = interactive
+		return ro
+	}
+}
+
 // initializeOptions represents the struct with all available options in Initialize function.
 type initializeOptions[T any] struct {
-	formGroups []FormGroup[T]
-	options    []tea.ProgramOption
+	answers     []AnswersSource
+	destdir     string
+	formGroups  []FormGroup[T]
+	interactive Interactive
+	options     []tea.ProgramOption
+	parsers     []Parser[T]
+	record      *recordOptions
+	replay      *recordOptions
+	review      bool
+	runner      func(ctx context.Context, form *huh.Form) error
 }
 
 // newInitializeOpt creates a new option struct with all input Option functions while taking care of default values.
 func newInitializeOpt[T any](opts ...InitializeOption[T]) initializeOptions[T] {
-	var ro initializeOptions[T]
+	ro := initializeOptions[T]{destdir: "."}
 	for _, opt := range opts {
 		if opt != nil {
 			ro = opt(ro)
@@ -71,3 +242,45 @@ func newInitializeOpt[T any](opts ...InitializeOption[T]) initializeOptions[T] {
 	}
 	return ro
 }
+
+// run runs input form with the runner option (see WithFormRunner).
+func (ro initializeOptions[T]) run(ctx context.Context, form *huh.Form) error {
+	if ro.runner == nil {
+		return form.RunWithContext(ctx)
+	}
+	return ro.runner(ctx, form)
+}
+
+// runForm returns true when the form must be run interactively according to the interactive option.
+func (ro initializeOptions[T]) runForm() bool {
+	switch ro.interactive {
+	case InteractiveNever:
+		return false
+	case InteractiveAuto:
+		return term.IsTerminal(os.Stdin.Fd())
+	default:
+		return true
+	}
+}
+
+// replayAnswers returns the answers sources of Initialize function,
+// starting with the replayed answers (see WithReplay) so that WithAnswers sources override them.
+//
+// It returns false when the form mustn't be run because replayed answers are up to date and must be skipped.
+func (ro initializeOptions[T]) replayAnswers() ([]AnswersSource, bool, error) {
+	if ro.replay == nil {
+		return ro.answers, true, nil
+	}
+
+	record, ok, err := readRecord(ro.replay.src)
+	if err != nil || !ok {
+		return ro.answers, true, err
+	}
+
+	sources := append([]AnswersSource{AnswersMap(record.Answers)}, ro.answers...)
+	if record.Version != ro.replay.version {
+		GetLogger().Warnf("answers of '%s' were recorded with version %d (current version is %d), please review them", ro.replay.src, record.Version, ro.replay.version)
+		return sources, true, nil
+	}
+	return sources, !ro.replay.skip, nil
+}
@@ -1,6 +1,10 @@
 package engine_test
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -10,6 +14,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/files"
+	"github.com/kickr-dev/engine/testutils"
 )
 
 // Reference: https://www.alanwood.net/demos/ansi.html
@@ -54,3 +60,383 @@ func TestInitialize(t *testing.T) {
 		assert.Equal(t, expected, config)
 	})
 }
+
+func TestInitializeAnswers(t *testing.T) {
+	ctx := t.Context()
+
+	required := func(s string) error {
+		if s == "" {
+			return engine.ErrRequiredField
+		}
+		return nil
+	}
+	group := func(c *testconfig) *huh.Group {
+		return huh.NewGroup(huh.NewInput().Key("str").Value(&c.Str).Validate(required))
+	}
+
+	t.Run("error_missing_answer", func(t *testing.T) {
+		// Act
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithInteractive[testconfig](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorIs(t, err, engine.ErrInvalidAnswers)
+		assert.ErrorIs(t, err, engine.ErrRequiredField)
+		assert.ErrorContains(t, err, "field 'str'")
+	})
+
+	t.Run("error_missing_answers", func(t *testing.T) {
+		// Arrange
+		var a, b, c string
+		input := func(key string, value *string) engine.FormGroup[testconfig] {
+			return func(*testconfig) *huh.Group {
+				return huh.NewGroup(huh.NewInput().Key(key).Value(value).Validate(required))
+			}
+		}
+		conditional := func(*testconfig) *huh.Group {
+			return huh.NewGroup(huh.NewInput().Key("c").Value(&c).Validate(required)).WithHideFunc(func() bool { return a == "" })
+		}
+
+		// Act
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(input("a", &a), group, input("b", &b), conditional, input("d", new(string))),
+			engine.WithInteractive[testconfig](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorIs(t, err, engine.ErrInvalidAnswers)
+		assert.ErrorContains(t, err, "field 'a'")
+		assert.ErrorContains(t, err, "field 'str'")
+		assert.ErrorContains(t, err, "field 'b'")
+		assert.NotContains(t, err.Error(), "field 'c'") // hidden since 'a' is empty
+		assert.ErrorContains(t, err, "field 'd'")
+	})
+
+	t.Run("error_unsupported_file", func(t *testing.T) {
+		// Act
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithAnswers[testconfig](engine.AnswersFile("answers.ini")),
+			engine.WithInteractive[testconfig](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorContains(t, err, "unsupported answers file format '.ini'")
+	})
+
+	t.Run("success_non_interactive", func(t *testing.T) {
+		// Arrange
+		src := filepath.Join(t.TempDir(), "answers.yml")
+		require.NoError(t, os.WriteFile(src, []byte("str: from file"), files.RwRR))
+		t.Setenv("TEST_ANSWERS_STR", "from env")
+
+		cases := []struct {
+			name     string
+			sources  []engine.AnswersSource
+			expected testconfig
+		}{
+			{name: "map", sources: []engine.AnswersSource{engine.AnswersMap(map[string]any{"str": "from map"})}, expected: testconfig{Str: "from map"}},
+			{name: "file", sources: []engine.AnswersSource{engine.AnswersFile(src)}, expected: testconfig{Str: "from file"}},
+			{name: "env_override", sources: []engine.AnswersSource{engine.AnswersFile(src), engine.AnswersEnv("TEST_ANSWERS_")}, expected: testconfig{Str: "from env"}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Act
+				config, err := engine.Initialize(ctx,
+					engine.WithFormGroups(group),
+					engine.WithAnswers[testconfig](tc.sources...),
+					engine.WithInteractive[testconfig](engine.InteractiveNever))
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, config)
+			})
+		}
+	})
+
+	t.Run("success_hidden_group", func(t *testing.T) {
+		// Arrange
+		var other string
+		hidden := func(*testconfig) *huh.Group {
+			return huh.NewGroup(huh.NewInput().Key("other").Value(&other).Validate(required)).WithHide(true)
+		}
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithFormGroups(hidden, group, hidden),
+			engine.WithAnswers[testconfig](engine.AnswersMap(map[string]any{"Str": "value"})),
+			engine.WithInteractive[testconfig](engine.InteractiveNever))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "value"}, config)
+	})
+
+	t.Run("success_default_values", func(t *testing.T) {
+		// Arrange
+		reader := strings.NewReader(defaultSubmit)
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithAnswers[testconfig](engine.AnswersMap(map[string]any{"str": "default"})),
+			engine.WithTeaOptions[testconfig](tea.WithInput(reader)))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "default"}, config)
+	})
+}
+
+func TestInitializeRecord(t *testing.T) {
+	ctx := t.Context()
+
+	group := func(c *testconfig) *huh.Group { return huh.NewGroup(huh.NewInput().Value(&c.Str)) }
+
+	t.Run("error_unsupported_format", func(t *testing.T) {
+		// Arrange
+		dst := filepath.Join(t.TempDir(), "answers.ini")
+
+		// Act
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithRecord[testconfig](dst, 1))
+
+		// Assert
+		assert.ErrorContains(t, err, "unsupported answers file format '.ini'")
+		assert.NoFileExists(t, dst)
+	})
+
+	t.Run("success_record", func(t *testing.T) {
+		// Arrange
+		dst := filepath.Join(t.TempDir(), "answers.yml")
+		reader := strings.NewReader("value" + defaultSubmit)
+
+		// Act
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithRecord[testconfig](dst, 2),
+			engine.WithTeaOptions[testconfig](tea.WithInput(reader)))
+
+		// Assert
+		require.NoError(t, err)
+		var record map[string]any
+		require.NoError(t, files.ReadYAML(os.DirFS(filepath.Dir(dst)), filepath.Base(dst), &record))
+		assert.Equal(t, map[string]any{"answers": map[string]any{"Str": "value"}, "version": uint64(2)}, record)
+	})
+
+	t.Run("success_replay_skip", func(t *testing.T) {
+		// Arrange
+		src := filepath.Join(t.TempDir(), "answers.json")
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithAnswers[testconfig](engine.AnswersMap(map[string]any{"str": "recorded"})),
+			engine.WithInteractive[testconfig](engine.InteractiveNever),
+			engine.WithRecord[testconfig](src, 1))
+		require.NoError(t, err)
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithReplay[testconfig](src, 1, true),
+			engine.WithTeaOptions[testconfig](tea.WithInput(strings.NewReader(""))))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "recorded"}, config)
+	})
+
+	t.Run("success_replay_version_mismatch", func(t *testing.T) {
+		// Arrange
+		src := filepath.Join(t.TempDir(), "answers.toml")
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithAnswers[testconfig](engine.AnswersMap(map[string]any{"str": "recorded"})),
+			engine.WithInteractive[testconfig](engine.InteractiveNever),
+			engine.WithRecord[testconfig](src, 1))
+		require.NoError(t, err)
+
+		reader := strings.NewReader("-reviewed" + defaultSubmit)
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithReplay[testconfig](src, 2, true),
+			engine.WithTeaOptions[testconfig](tea.WithInput(reader)))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "recorded-reviewed"}, config)
+	})
+
+	t.Run("success_replay_missing", func(t *testing.T) {
+		// Arrange
+		src := filepath.Join(t.TempDir(), "answers.json")
+		reader := strings.NewReader("value" + defaultSubmit)
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithReplay[testconfig](src, 1, true),
+			engine.WithTeaOptions[testconfig](tea.WithInput(reader)))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "value"}, config)
+	})
+}
+
+func TestInitializeParsers(t *testing.T) {
+	ctx := t.Context()
+
+	group := func(c *testconfig) *huh.Group { return huh.NewGroup(huh.NewInput().Value(&c.Str)) }
+	parser := func(_ context.Context, destdir string, c *testconfig) error {
+		c.Str = filepath.Base(destdir)
+		return nil
+	}
+
+	t.Run("error_parser", func(t *testing.T) {
+		// Arrange
+		parser := func(context.Context, string, *testconfig) error { return errors.New("parse error") }
+
+		// Act
+		_, err := engine.Initialize(ctx,
+			engine.WithFormGroups(group),
+			engine.WithParsers(parser),
+			engine.WithInteractive[testconfig](engine.InteractiveNever))
+
+		// Assert
+		assert.ErrorContains(t, err, "parse error")
+	})
+
+	t.Run("success_detected", func(t *testing.T) {
+		// Arrange
+		destdir := filepath.Join(t.TempDir(), "project")
+		reader := strings.NewReader(defaultSubmit)
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithDestdir[testconfig](destdir),
+			engine.WithFormGroups(group),
+			engine.WithParsers(parser),
+			engine.WithTeaOptions[testconfig](tea.WithInput(reader)))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "project"}, config)
+	})
+
+	t.Run("success_answers_override", func(t *testing.T) {
+		// Arrange
+		destdir := filepath.Join(t.TempDir(), "project")
+
+		// Act
+		config, err := engine.Initialize(ctx,
+			engine.WithAnswers[testconfig](engine.AnswersMap(map[string]any{"str": "answer"})),
+			engine.WithDestdir[testconfig](destdir),
+			engine.WithFormGroups(group),
+			engine.WithInteractive[testconfig](engine.InteractiveNever),
+			engine.WithParsers(parser))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "answer"}, config)
+	})
+}
+
+func TestDetectedOptions(t *testing.T) {
+	options := huh.NewOptions("npm", "pnpm", "yarn")
+
+	cases := []struct {
+		name     string
+		detected string
+		expected []huh.Option[string]
+	}{
+		{
+			name:     "not_detected",
+			expected: options,
+		},
+		{
+			name:     "detected_option",
+			detected: "pnpm",
+			expected: []huh.Option[string]{huh.NewOption("npm", "npm"), huh.NewOption("pnpm (detected)", "pnpm"), huh.NewOption("yarn", "yarn")},
+		},
+		{
+			name:     "detected_other",
+			detected: "bun",
+			expected: []huh.Option[string]{huh.NewOption("bun (detected)", "bun"), huh.NewOption("npm", "npm"), huh.NewOption("pnpm", "pnpm"), huh.NewOption("yarn", "yarn")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual := engine.DetectedOptions(tc.detected, options...)
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, huh.NewOptions("npm", "pnpm", "yarn"), options) // input options are untouched
+		})
+	}
+}
+
+func TestInitializeReview(t *testing.T) {
+	group := func(c *testconfig) *huh.Group {
+		return huh.NewGroup(huh.NewInput().Key("str").Value(&c.Str).Validate(func(s string) error {
+			if s == "" {
+				return engine.ErrRequiredField
+			}
+			return nil
+		}))
+	}
+
+	t.Run("error_aborted", func(t *testing.T) {
+		// Arrange
+		scripts := []testutils.FormScript{testutils.Script("value", testutils.KeyEnter)}
+
+		// Act
+		_, _, err := testutils.Initialize(t, scripts,
+			engine.WithFormGroups(group),
+			engine.WithReview[testconfig](true))
+
+		// Assert
+		assert.ErrorIs(t, err, huh.ErrUserAborted)
+	})
+
+	t.Run("success_confirm", func(t *testing.T) {
+		// Arrange
+		scripts := []testutils.FormScript{
+			testutils.Script(testutils.KeyEnter, "value", testutils.KeyEnter),
+			testutils.Script(testutils.KeyEnter),
+		}
+
+		// Act
+		config, errs, err := testutils.Initialize(t, scripts,
+			engine.WithFormGroups(group),
+			engine.WithReview[testconfig](true))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "value"}, config)
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], engine.ErrRequiredField)
+	})
+
+	t.Run("success_edit", func(t *testing.T) {
+		// Arrange
+		scripts := []testutils.FormScript{
+			testutils.Script("value", testutils.KeyEnter),
+			testutils.Script(testutils.KeyDown, testutils.KeyEnter), // edit page 1
+			testutils.Script("-edited", testutils.KeyEnter),
+			testutils.Script(testutils.KeyEnter), // confirm
+		}
+
+		// Act
+		config, errs, err := testutils.Initialize(t, scripts,
+			engine.WithFormGroups(group),
+			engine.WithReview[testconfig](true))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, testconfig{Str: "value-edited"}, config)
+		assert.Empty(t, errs)
+	})
+}
@@ -0,0 +1,65 @@
+// Package values provides raw values helpers (environment variables reading and typed decoding)
+// shared by engine Initialize answers and config layers.
+package values
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// FromEnv returns the values of all environment variables starting with prefix.
+//
+// The prefix is removed from variables names, the remaining name is lowercased
+// and double underscores are used as nesting separators, e.g. with "KICKR_" prefix,
+// KICKR_MAINTAINER__NAME=jane gives {"maintainer": {"name": "jane"}}.
+func FromEnv(prefix string) map[string]any {
+	values := map[string]any{}
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		key, ok := strings.CutPrefix(name, prefix)
+		if !ok || key == "" {
+			continue
+		}
+
+		parts := strings.Split(strings.ToLower(key), "__")
+		current := values
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = value
+	}
+	return values
+}
+
+// Decode decodes input values into out (a pointer).
+//
+// Values keys are matched against out fields names read from tags (a comma-separated list of tag names,
+// the first one present on a field being used, e.g. "yaml,toml,json") or their name otherwise,
+// case insensitively and ignoring underscores and dashes (see NormalizeKey), e.g. "maintainer_name" matches MaintainerName.
+// Scalar values are converted when needed (e.g. "true" into a bool), since environment variables are strings.
+func Decode(values map[string]any, out any, tags string) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		MatchName:        func(key, field string) bool { return NormalizeKey(key) == NormalizeKey(field) },
+		Result:           out,
+		Squash:           true, // embedded structs are flattened like with JSON
+		TagName:          tags,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return fmt.Errorf("new decoder: %w", err)
+	}
+	return decoder.Decode(values)
+}
+
+// NormalizeKey returns the comparable version of a key, lowercased and without underscores and dashes.
+func NormalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
@@ -0,0 +1,72 @@
+// Package versions provides semantic versions helpers shared by engine template functions and parser.VCS.
+package versions
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ErrUnknownPart is returned by Bump when the part to bump isn't "major", "minor" or "patch".
+var ErrUnknownPart = errors.New("unknown version part")
+
+// Parse returns the semantic versions of input tags starting with prefix, sorted in ascending order.
+//
+// Tags not starting with prefix or not being a valid semantic version (major, minor and patch) after it are ignored,
+// prereleases are ignored too unless asked.
+func Parse(tags []string, prefix string, prereleases bool) []*semver.Version {
+	versions := make([]*semver.Version, 0, len(tags))
+	for _, tag := range tags {
+		raw, ok := strings.CutPrefix(tag, prefix)
+		if !ok {
+			continue
+		}
+		version, err := semver.StrictNewVersion(raw)
+		if err != nil || (!prereleases && version.Prerelease() != "") {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	slices.SortStableFunc(versions, func(a, b *semver.Version) int { return a.Compare(b) })
+	return versions
+}
+
+// Split splits input tag into its prefix and its semantic version.
+//
+// It returns false when input tag doesn't end with a valid semantic version (major, minor and patch).
+func Split(tag string) (string, *semver.Version, bool) {
+	for i := range len(tag) {
+		// a version starts with a digit, not preceded by another digit
+		if !isDigit(tag[i]) || (i > 0 && isDigit(tag[i-1])) {
+			continue
+		}
+		if version, err := semver.StrictNewVersion(tag[i:]); err == nil {
+			return tag[:i], version, true
+		}
+	}
+	return "", nil, false
+}
+
+// Bump returns the next version of input version according to the part to bump ("major", "minor" or "patch").
+func Bump(version *semver.Version, part string) (*semver.Version, error) {
+	var next semver.Version
+	switch part {
+	case "major":
+		next = version.IncMajor()
+	case "minor":
+		next = version.IncMinor()
+	case "patch":
+		next = version.IncPatch()
+	default:
+		return nil, fmt.Errorf("%w '%s', must be one of 'major', 'minor' or 'patch'", ErrUnknownPart, part)
+	}
+	return &next, nil
+}
+
+// isDigit returns true when input byte is an ASCII digit.
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Comment represents the comment syntax of a given file type.
+type Comment struct {
+	// End is the end delimiter of a comment, i.e. */ or -->, etc.
+	//
+	// It's empty for line comments.
+	End string
+
+	// Start is the start delimiter of a comment, i.e. // or # or <!--, etc.
+	Start string
+}
+
+var (
+	commentDash    = Comment{Start: "--"}
+	commentHash    = Comment{Start: "#"}
+	commentHTML    = Comment{Start: "<!--", End: "-->"}
+	commentPercent = Comment{Start: "%"}
+	commentRem     = Comment{Start: "@REM"} // not echoed, even before "@echo off"
+	commentSlash   = Comment{Start: "//"}
+	commentStar    = Comment{Start: "/*", End: "*/"}
+)
+
+// commentExtensions is the association between a file extension and its comment syntax.
+var commentExtensions = map[string]Comment{
+	".bash":       commentHash,
+	".bat":        commentRem,
+	".c":          commentSlash,
+	".cc":         commentSlash,
+	".cfg":        commentHash,
+	".cjs":        commentSlash,
+	".cmd":        commentRem,
+	".conf":       commentHash,
+	".cpp":        commentSlash,
+	".cs":         commentSlash,
+	".css":        commentStar,
+	".dart":       commentSlash,
+	".dockerfile": commentHash,
+	".env":        commentHash,
+	".erl":        commentPercent,
+	".go":         commentSlash,
+	".gradle":     commentSlash,
+	".groovy":     commentSlash,
+	".h":          commentSlash,
+	".hcl":        commentHash,
+	".hpp":        commentSlash,
+	".hs":         commentDash,
+	".htm":        commentHTML,
+	".html":       commentHTML,
+	".java":       commentSlash,
+	".js":         commentSlash,
+	".json5":      commentSlash,
+	".jsonc":      commentSlash,
+	".jsx":        commentSlash,
+	".just":       commentHash,
+	".kt":         commentSlash,
+	".kts":        commentSlash,
+	".lua":        commentDash,
+	".md":         commentHTML,
+	".mjs":        commentSlash,
+	".mk":         commentHash,
+	".pl":         commentHash,
+	".properties": commentHash,
+	".proto":      commentSlash,
+	".ps1":        commentHash,
+	".py":         commentHash,
+	".r":          commentHash,
+	".rb":         commentHash,
+	".rs":         commentSlash,
+	".scala":      commentSlash,
+	".scss":       commentSlash,
+	".sh":         commentHash,
+	".sql":        commentDash,
+	".svg":        commentHTML,
+	".swift":      commentSlash,
+	".tex":        commentPercent,
+	".tf":         commentHash,
+	".toml":       commentHash,
+	".ts":         commentSlash,
+	".tsx":        commentSlash,
+	".vue":        commentHTML,
+	".xml":        commentHTML,
+	".yaml":       commentHash,
+	".yml":        commentHash,
+	".zsh":        commentHash,
+}
+
+// commentNames is the association between a file name (without extension) and its comment syntax.
+var commentNames = map[string]Comment{
+	".dockerignore":  commentHash,
+	".editorconfig":  commentHash,
+	".gitattributes": commentHash,
+	".gitignore":     commentHash,
+	".gitmodules":    commentHash,
+	".helmignore":    commentHash,
+	"CODEOWNERS":     commentHash,
+	"Containerfile":  commentHash,
+	"Dockerfile":     commentHash,
+	"Justfile":       commentHash,
+	"Makefile":       commentHash,
+	"justfile":       commentHash,
+	"makefile":       commentHash,
+}
+
+// CommentFor returns the comment syntax associated to input out file name (or extension).
+//
+// It returns false in case the file type doesn't support comments (e.g. JSON) or is unknown.
+func CommentFor(out string) (Comment, bool) {
+	name := filepath.Base(out)
+	if comment, ok := commentNames[name]; ok {
+		return comment, true
+	}
+	if isDockerfile(name) {
+		return commentHash, true
+	}
+	comment, ok := commentExtensions[strings.ToLower(filepath.Ext(name))]
+	return comment, ok
+}
+
+// Notice returns the generated notice associated to input generator name,
+// i.e. "Code generated by <generator>; DO NOT EDIT.".
+//
+// It's also available in FuncMap as "notice" to be used in templates of files not supporting comments,
+// like JSON where a dedicated key can be used:
+//
+//	{
+//		"//": "{{ notice "kickr" }}",
+//		...
+//	}
+func Notice(generator string) string {
+	return fmt.Sprintf("Code generated by %s; DO NOT EDIT.", generator)
+}
+
+// InjectNotice injects the generated notice associated to input generator at the top of the input content,
+// with the comment syntax associated to out (see CommentFor).
+//
+// The notice is injected after any shebang line, XML declaration, YAML directives and document start marker,
+// Dockerfile parser directives (e.g. "# syntax=docker/dockerfile:1"), Markdown front matter,
+// Python encoding declaration or CSS "@charset" rule.
+//
+// The content is returned unchanged when out doesn't support comments (see Notice for an alternative)
+// or when content already holds a generated notice.
+func InjectNotice(content []byte, out, generator string) []byte {
+	comment, ok := CommentFor(out)
+	if _, generated := GeneratedBy(content); !ok || generated {
+		return content
+	}
+
+	line := comment.Start + " " + Notice(generator)
+	if comment.End != "" {
+		line += " " + comment.End
+	}
+
+	index := headerLength(content, out)
+	result := make([]byte, 0, len(content)+len(line)+2)
+	result = append(result, content[:index]...)
+	if index > 0 && content[index-1] != '\n' {
+		result = append(result, '\n') // header without final newline
+	}
+	result = append(result, line...)
+	result = append(result, '\n')
+	return append(result, content[index:]...)
+}
+
+// headerLength returns the length of the header lines of content generated in out,
+// i.e. lines that must stay at the top of the file (shebang, XML declaration, YAML directives, etc.).
+func headerLength(content []byte, out string) int {
+	ext := strings.ToLower(filepath.Ext(out))
+	if ext == ".md" {
+		return frontMatterLength(content)
+	}
+	yaml := ext == ".yml" || ext == ".yaml"
+	python := ext == ".py"
+	css := ext == ".css" || ext == ".scss"
+	dockerfile := ext == ".dockerfile" || isDockerfile(filepath.Base(out))
+
+	var index int
+	for number := 1; index < len(content); number++ {
+		end := lineLength(content[index:])
+		line := bytes.TrimSpace(content[index : index+end])
+
+		switch {
+		case number == 1 && (bytes.HasPrefix(line, []byte("#!")) || bytes.HasPrefix(line, []byte("<?xml"))):
+		case number == 1 && css && bytes.HasPrefix(line, []byte("@charset")):
+		case number <= 2 && python && pythonCoding.Match(line): // PEP 263 encoding declaration on the first two lines
+		case yaml && bytes.HasPrefix(line, []byte("%")):
+		case yaml && bytes.Equal(line, []byte("---")):
+			return index + end // document start marker is the last possible header line
+		case dockerfile && dockerDirective.Match(line):
+		default:
+			return index
+		}
+		index += end
+	}
+	return index
+}
+
+// dockerDirective matches a Dockerfile parser directive line (e.g. "# syntax=docker/dockerfile:1" or "# escape=`"),
+// which must stay at the top of the Dockerfile, before any comment, to be taken into account.
+var dockerDirective = regexp.MustCompile(`^#\s*[a-zA-Z][a-zA-Z0-9]*\s*=\s*\S`)
+
+// pythonCoding matches a Python source code encoding declaration (e.g. "# -*- coding: utf-8 -*-"), see PEP 263.
+var pythonCoding = regexp.MustCompile(`^#.*?coding[:=][ \t]*[-_.a-zA-Z0-9]+`)
+
+// frontMatterLength returns the length of the front matter (YAML between "---" lines or TOML between "+++" lines)
+// at the top of a Markdown content, 0 when there's none.
+func frontMatterLength(content []byte) int {
+	end := lineLength(content)
+	delimiter := bytes.TrimSpace(content[:end])
+	if !bytes.Equal(delimiter, []byte("---")) && !bytes.Equal(delimiter, []byte("+++")) {
+		return 0
+	}
+
+	for index := end; index < len(content); {
+		end := lineLength(content[index:])
+		if bytes.Equal(bytes.TrimSpace(content[index:index+end]), delimiter) {
+			return index + end
+		}
+		index += end
+	}
+	return 0 // unclosed front matter isn't one
+}
+
+// lineLength returns the length of the first line of content, including its final '\n' if any.
+func lineLength(content []byte) int {
+	end := bytes.IndexByte(content, '\n')
+	if end < 0 {
+		return len(content)
+	}
+	return end + 1
+}
+
+// isDockerfile returns true when input file name is a Dockerfile or a Containerfile (e.g. "Dockerfile.dev").
+func isDockerfile(name string) bool {
+	return name == "Dockerfile" || name == "Containerfile" ||
+		strings.HasPrefix(name, "Dockerfile.") || strings.HasPrefix(name, "Containerfile.")
+}
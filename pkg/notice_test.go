@@ -0,0 +1,89 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	engine "github.com/kickr-dev/engine/pkg"
+)
+
+func TestCommentFor(t *testing.T) {
+	cases := []struct {
+		out      string
+		expected engine.Comment
+		ok       bool
+	}{
+		{"main.go", engine.Comment{Start: "//"}, true},
+		{"path/to/.gitlab-ci.yml", engine.Comment{Start: "#"}, true},
+		{"README.md", engine.Comment{Start: "<!--", End: "-->"}, true},
+		{"style.CSS", engine.Comment{Start: "/*", End: "*/"}, true},
+		{"schema.sql", engine.Comment{Start: "--"}, true},
+		{"Dockerfile", engine.Comment{Start: "#"}, true},
+		{"Dockerfile.dev", engine.Comment{Start: "#"}, true},
+		{"package.json", engine.Comment{}, false},
+		{"unknown", engine.Comment{}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.out, func(t *testing.T) {
+			// Act
+			comment, ok := engine.CommentFor(tc.out)
+
+			// Assert
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, comment)
+		})
+	}
+}
+
+func TestNotice(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Act
+		notice := engine.Notice("kickr")
+
+		// Assert
+		assert.Equal(t, "Code generated by kickr; DO NOT EDIT.", notice)
+	})
+}
+
+func TestInjectNotice(t *testing.T) {
+	cases := []struct {
+		name     string
+		out      string
+		content  string
+		expected string
+	}{
+		{"go", "main.go", "package main\n", "// Code generated by kickr; DO NOT EDIT.\npackage main\n"},
+		{"markdown", "README.md", "# Title\n", "<!-- Code generated by kickr; DO NOT EDIT. -->\n# Title\n"},
+		{"empty", "file.yml", "", "# Code generated by kickr; DO NOT EDIT.\n"},
+		{"json", "package.json", "{}\n", "{}\n"},
+		{"already_present", "main.go", "// Code generated by other; DO NOT EDIT.\n", "// Code generated by other; DO NOT EDIT.\n"},
+		{"shebang", "script.sh", "#!/bin/sh\necho hi\n", "#!/bin/sh\n# Code generated by kickr; DO NOT EDIT.\necho hi\n"},
+		{"shebang_no_newline", "script.sh", "#!/bin/sh", "#!/bin/sh\n# Code generated by kickr; DO NOT EDIT.\n"},
+		{"xml", "pom.xml", "<?xml version=\"1.0\"?>\n<project/>\n", "<?xml version=\"1.0\"?>\n<!-- Code generated by kickr; DO NOT EDIT. -->\n<project/>\n"},
+		{"yaml_document", "file.yaml", "---\nkey: value\n", "---\n# Code generated by kickr; DO NOT EDIT.\nkey: value\n"},
+		{"yaml_directives", "file.yml", "%YAML 1.2\n---\nkey: value\n", "%YAML 1.2\n---\n# Code generated by kickr; DO NOT EDIT.\nkey: value\n"},
+		{"markdown_front_matter", "README.md", "---\ntitle: x\n---\n# Title\n", "---\ntitle: x\n---\n<!-- Code generated by kickr; DO NOT EDIT. -->\n# Title\n"},
+		{"markdown_toml_front_matter", "index.md", "+++\ntitle = 'x'\n+++", "+++\ntitle = 'x'\n+++\n<!-- Code generated by kickr; DO NOT EDIT. -->\n"},
+		{"markdown_unclosed_dashes", "file.md", "---\ntext\n", "<!-- Code generated by kickr; DO NOT EDIT. -->\n---\ntext\n"},
+		{"dockerfile_directives", "Dockerfile", "# syntax=docker/dockerfile:1\n# escape=`\nFROM scratch\n", "# syntax=docker/dockerfile:1\n# escape=`\n# Code generated by kickr; DO NOT EDIT.\nFROM scratch\n"},
+		{"containerfile_directive", "build/Containerfile.dev", "#syntax = docker/dockerfile:1\nFROM scratch\n", "#syntax = docker/dockerfile:1\n# Code generated by kickr; DO NOT EDIT.\nFROM scratch\n"},
+		{"batch", "build.bat", "@echo off\r\n", "@REM Code generated by kickr; DO NOT EDIT.\n@echo off\r\n"},
+		{"python_coding", "main.py", "# -*- coding: latin-1 -*-\nimport os\n", "# -*- coding: latin-1 -*-\n# Code generated by kickr; DO NOT EDIT.\nimport os\n"},
+		{"python_shebang_coding", "main.py", "#!/usr/bin/env python\n# vim: set fileencoding=utf-8 :\nimport os\n", "#!/usr/bin/env python\n# vim: set fileencoding=utf-8 :\n# Code generated by kickr; DO NOT EDIT.\nimport os\n"},
+		{"python_late_coding", "main.py", "import os\n# coding: utf-8\n", "# Code generated by kickr; DO NOT EDIT.\nimport os\n# coding: utf-8\n"},
+		{"css_charset", "style.css", "@charset \"UTF-8\";\nbody {}\n", "@charset \"UTF-8\";\n/* Code generated by kickr; DO NOT EDIT. */\nbody {}\n"},
+		{"dockerfile_comment", "app.dockerfile", "# base image\nFROM scratch\n", "# Code generated by kickr; DO NOT EDIT.\n# base image\nFROM scratch\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			content := engine.InjectNotice([]byte(tc.content), tc.out, "kickr")
+
+			// Assert
+			assert.Equal(t, tc.expected, string(content))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/kickr-dev/engine/pkg/internal/versions"
+)
+
+// ErrUnknownVersionPart is returned by BumpVersion when the part to bump isn't "major", "minor" or "patch".
+var ErrUnknownVersionPart = versions.ErrUnknownPart
+
+// ParseVersions returns the semantic versions of input tags starting with prefix (e.g. "v" or "module/v" for go.work modules),
+// sorted in ascending order.
+//
+// Tags not starting with prefix or not being a valid semantic version (major, minor and patch) after it are ignored,
+// prereleases (e.g. "1.2.0-rc.1") are ignored too unless asked.
+func ParseVersions(tags []string, prefix string, prereleases bool) []*semver.Version {
+	return versions.Parse(tags, prefix, prereleases)
+}
+
+// SplitVersion splits input tag into its prefix (e.g. "v" or "module/v") and its semantic version.
+//
+// It returns false when input tag doesn't end with a valid semantic version (major, minor and patch).
+func SplitVersion(tag string) (string, *semver.Version, bool) {
+	return versions.Split(tag)
+}
+
+// BumpVersion returns the next version of input version according to the part to bump ("major", "minor" or "patch").
+//
+// Prerelease and metadata are dropped, e.g. bumping "minor" of "1.2.3-rc.1" gives "1.3.0"
+// and bumping "patch" of "1.2.3-rc.1" gives its release "1.2.3".
+func BumpVersion(version *semver.Version, part string) (*semver.Version, error) {
+	return versions.Bump(version, part)
+}
+
+// Versions returns the semantic versions of VCS tags starting with prefix (e.g. "v" or "module/v" for go.work modules),
+// sorted in ascending order (see ParseVersions).
+func (v VCS) Versions(prefix string, prereleases bool) []*semver.Version {
+	return ParseVersions(v.Tags, prefix, prereleases)
+}
+
+// LatestVersion returns the latest semantic version of VCS tags starting with prefix, nil when there's none.
+func (v VCS) LatestVersion(prefix string, prereleases bool) *semver.Version {
+	all := v.Versions(prefix, prereleases)
+	if len(all) == 0 {
+		return nil
+	}
+	return all[len(all)-1]
+}
+
+// NextVersion returns the next version after the latest (non prerelease) semantic version of VCS tags starting with prefix,
+// according to the part to bump ("major", "minor" or "patch"). Versions start from "0.0.0" when there's none.
+func (v VCS) NextVersion(prefix, part string) (*semver.Version, error) {
+	latest := v.LatestVersion(prefix, false)
+	if latest == nil {
+		latest = semver.New(0, 0, 0, "", "")
+	}
+	return BumpVersion(latest, part)
+}
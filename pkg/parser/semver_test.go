@@ -0,0 +1,134 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kickr-dev/engine/pkg/parser"
+)
+
+func versionStrings(versions []*semver.Version) []string {
+	out := make([]string, 0, len(versions))
+	for _, version := range versions {
+		out = append(out, version.String())
+	}
+	return out
+}
+
+func TestParseVersions(t *testing.T) {
+	tags := []string{"v1.10.0", "v1.2.0", "v2.0.0-rc.1", "1.5.0", "api/v0.3.0", "api/v0.1.0", "v1.3", "latest", "v1.9.1"}
+
+	cases := []struct {
+		name        string
+		prefix      string
+		prereleases bool
+		expected    []string
+	}{
+		{name: "prefix_v", prefix: "v", expected: []string{"1.2.0", "1.9.1", "1.10.0"}},
+		{name: "prefix_v_prereleases", prefix: "v", prereleases: true, expected: []string{"1.2.0", "1.9.1", "1.10.0", "2.0.0-rc.1"}},
+		{name: "no_prefix", expected: []string{"1.5.0"}},
+		{name: "module_prefix", prefix: "api/v", expected: []string{"0.1.0", "0.3.0"}},
+		{name: "unknown_prefix", prefix: "web/v", expected: []string{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			versions := parser.ParseVersions(tags, tc.prefix, tc.prereleases)
+
+			// Assert
+			assert.Equal(t, tc.expected, versionStrings(versions))
+		})
+	}
+}
+
+func TestSplitVersion(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		for _, tag := range []string{"", "latest", "v1.2", "release-2024"} {
+			// Act
+			_, _, ok := parser.SplitVersion(tag)
+
+			// Assert
+			assert.False(t, ok, tag)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		cases := map[string][2]string{
+			"1.2.3":              {"", "1.2.3"},
+			"v1.2.3":             {"v", "1.2.3"},
+			"api/v0.1.0-rc.1":    {"api/v", "0.1.0-rc.1"},
+			"tools/v2/v2.10.0":   {"tools/v2/v", "2.10.0"},
+			"release-12.0.1+git": {"release-", "12.0.1+git"},
+		}
+		for tag, expected := range cases {
+			// Act
+			prefix, version, ok := parser.SplitVersion(tag)
+
+			// Assert
+			require.True(t, ok, tag)
+			assert.Equal(t, expected[0], prefix)
+			assert.Equal(t, expected[1], version.String())
+		}
+	})
+}
+
+func TestBumpVersion(t *testing.T) {
+	t.Run("error_unknown_part", func(t *testing.T) {
+		// Act
+		_, err := parser.BumpVersion(semver.MustParse("1.2.3"), "build")
+
+		// Assert
+		assert.ErrorIs(t, err, parser.ErrUnknownVersionPart)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		cases := []struct {
+			version  string
+			part     string
+			expected string
+		}{
+			{version: "1.2.3", part: "major", expected: "2.0.0"},
+			{version: "1.2.3", part: "minor", expected: "1.3.0"},
+			{version: "1.2.3", part: "patch", expected: "1.2.4"},
+			{version: "1.2.3-rc.1", part: "minor", expected: "1.3.0"},
+			{version: "1.2.3-rc.1", part: "patch", expected: "1.2.3"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.version+"_"+tc.part, func(t *testing.T) {
+				// Act
+				next, err := parser.BumpVersion(semver.MustParse(tc.version), tc.part)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, next.String())
+			})
+		}
+	})
+}
+
+func TestVCSVersions(t *testing.T) {
+	vcs := parser.VCS{Tags: []string{"v0.1.0", "v0.2.0-rc.1", "v0.1.1", "api/v1.0.0"}}
+
+	t.Run("latest", func(t *testing.T) {
+		// Act & Assert
+		assert.Equal(t, "0.1.1", vcs.LatestVersion("v", false).String())
+		assert.Equal(t, "0.2.0-rc.1", vcs.LatestVersion("v", true).String())
+		assert.Equal(t, "1.0.0", vcs.LatestVersion("api/v", false).String())
+		assert.Nil(t, vcs.LatestVersion("web/v", false))
+	})
+
+	t.Run("next", func(t *testing.T) {
+		// Act
+		next, err := vcs.NextVersion("v", "minor")
+		first, firstErr := vcs.NextVersion("web/v", "patch")
+
+		// Assert
+		require.NoError(t, err)
+		require.NoError(t, firstErr)
+		assert.Equal(t, "0.2.0", next.String())
+		assert.Equal(t, "0.0.1", first.String())
+	})
+}
@@ -0,0 +1,16 @@
+/*
+Package progress exposes a bubbletea model rendering engine.Generate progress from the events it emits.
+
+	program := tea.NewProgram(progress.New())
+	engine.Configure(engine.WithEventSink(progress.Sink(program)))
+
+	go func() {
+		err := engine.Generate(ctx, destdir, config, parsers, generators)
+		program.Send(progress.DoneMsg{Err: err})
+	}()
+
+	if _, err := program.Run(); err != nil {
+		// handle err
+	}
+*/
+package progress
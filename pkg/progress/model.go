@@ -0,0 +1,149 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	engine "github.com/kickr-dev/engine/pkg"
+)
+
+// DoneMsg must be sent to the program once engine.Generate returned, with its error, for the model to quit.
+type DoneMsg struct {
+	// Err is the error returned by engine.Generate.
+	Err error
+}
+
+// Model is the bubbletea model rendering engine.Generate progress from engine.Event messages (see Sink).
+type Model struct {
+	done       bool
+	err        error
+	generators []generator
+	parsers    int
+}
+
+var _ tea.Model = Model{} // ensure interface is implemented
+
+// generator is the progress of a given generator.
+type generator struct {
+	current  string
+	err      error
+	finished bool
+	patched  int
+	removed  int
+	skipped  int
+	written  int
+}
+
+// New creates a new progress Model.
+func New() Model {
+	return Model{}
+}
+
+// Sink returns an engine.EventSink forwarding all events to input program,
+// to be given to engine.WithEventSink.
+//
+// The program must be running for events to be processed, otherwise generation is blocked.
+func Sink(program *tea.Program) engine.EventSink {
+	return func(event engine.Event) {
+		program.Send(event)
+	}
+}
+
+// Init implements tea.Model.
+func (Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case engine.Event:
+		return m.apply(msg), nil
+	case DoneMsg:
+		m.done = true
+		m.err = msg.Err
+		return m, tea.Quit
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// View implements tea.Model.
+func (m Model) View() tea.View {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Parsing: %d parser(s) done\n", m.parsers)
+	for index, g := range m.generators {
+		fmt.Fprintf(&builder, "Generator %d: %s\n", index, g.status())
+	}
+
+	switch {
+	case m.done && m.err != nil:
+		fmt.Fprintf(&builder, "Generation failed: %v\n", m.err)
+	case m.done:
+		builder.WriteString("Generation done\n")
+	}
+	return tea.NewView(builder.String())
+}
+
+// Err returns the error given with DoneMsg, if any.
+func (m Model) Err() error {
+	return m.err
+}
+
+// apply updates the model with input event.
+func (m Model) apply(event engine.Event) Model {
+	if event.Index < 0 {
+		return m // event emitted outside of engine.Generate
+	}
+
+	switch event.Kind {
+	case engine.EventParserFinished:
+		m.parsers++
+		return m
+	case engine.EventParserStarted:
+		return m
+	}
+
+	// copy generators to keep the model immutable between updates
+	generators := make([]generator, max(len(m.generators), event.Index+1))
+	copy(generators, m.generators)
+	g := &generators[event.Index]
+
+	switch event.Kind {
+	case engine.EventGeneratorFinished:
+		g.finished = true
+		g.err = event.Err
+	case engine.EventTemplateRendered:
+		g.current = event.Path
+	case engine.EventFileWritten:
+		g.written++
+	case engine.EventFileSkipped:
+		g.skipped++
+	case engine.EventFileRemoved:
+		g.removed++
+	case engine.EventPatchApplied:
+		g.patched++
+	}
+	m.generators = generators
+	return m
+}
+
+// status returns the human readable progress of the generator.
+func (g generator) status() string {
+	counts := fmt.Sprintf("%d written, %d skipped, %d removed, %d patched", g.written, g.skipped, g.removed, g.patched)
+	switch {
+	case g.finished && g.err != nil:
+		return fmt.Sprintf("failed (%s): %v", counts, g.err)
+	case g.finished:
+		return fmt.Sprintf("done (%s)", counts)
+	case g.current != "":
+		return fmt.Sprintf("running (%s), current '%s'", counts, g.current)
+	default:
+		return fmt.Sprintf("running (%s)", counts)
+	}
+}
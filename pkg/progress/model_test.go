@@ -0,0 +1,68 @@
+package progress_test
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/progress"
+)
+
+func TestModel(t *testing.T) {
+	update := func(t *testing.T, model tea.Model, msgs ...tea.Msg) tea.Model {
+		t.Helper()
+		for _, msg := range msgs {
+			model, _ = model.Update(msg)
+		}
+		return model
+	}
+
+	t.Run("success_running", func(t *testing.T) {
+		// Arrange
+		msgs := []tea.Msg{
+			engine.Event{Index: 0, Kind: engine.EventParserStarted},
+			engine.Event{Index: 0, Kind: engine.EventParserFinished},
+			engine.Event{Index: 1, Kind: engine.EventGeneratorStarted},
+			engine.Event{Index: 1, Kind: engine.EventTemplateRendered, Path: "README.md"},
+			engine.Event{Index: 1, Kind: engine.EventFileWritten, Path: "README.md"},
+			engine.Event{Index: -1, Kind: engine.EventFileWritten, Path: "ignored.md"},
+		}
+
+		// Act
+		model := update(t, progress.New(), msgs...)
+
+		// Assert
+		expected := "Parsing: 1 parser(s) done\n" +
+			"Generator 0: running (0 written, 0 skipped, 0 removed, 0 patched)\n" +
+			"Generator 1: running (1 written, 0 skipped, 0 removed, 0 patched), current 'README.md'\n"
+		assert.Equal(t, expected, model.View().Content)
+	})
+
+	t.Run("success_done", func(t *testing.T) {
+		// Arrange
+		msgs := []tea.Msg{
+			engine.Event{Index: 0, Kind: engine.EventGeneratorStarted},
+			engine.Event{Index: 0, Kind: engine.EventFileSkipped},
+			engine.Event{Index: 0, Kind: engine.EventFileRemoved},
+			engine.Event{Index: 0, Kind: engine.EventPatchApplied},
+			engine.Event{Index: 0, Kind: engine.EventGeneratorFinished, Err: errors.New("some error")},
+		}
+		model := update(t, progress.New(), msgs...)
+
+		// Act
+		model, cmd := model.Update(progress.DoneMsg{Err: engine.ErrFailedGeneration})
+
+		// Assert
+		require.NotNil(t, cmd)
+		assert.IsType(t, tea.QuitMsg{}, cmd())
+		expected := "Parsing: 0 parser(s) done\n" +
+			"Generator 0: failed (0 written, 1 skipped, 1 removed, 1 patched): some error\n" +
+			"Generation failed: some error(s) occurred during generation\n"
+		assert.Equal(t, expected, model.View().Content)
+		assert.ErrorIs(t, model.(progress.Model).Err(), engine.ErrFailedGeneration)
+	})
+}
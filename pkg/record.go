@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// answersRecord is the representation of an answers file written with WithRecord.
+type answersRecord struct {
+	Answers map[string]any `json:"answers" toml:"answers" yaml:"answers"`
+	Version int            `json:"version" toml:"version" yaml:"version"`
+}
+
+// WithRecord saves the configuration resulting of a successful Initialize into dst file
+// (YAML, JSON or TOML according to its extension) alongside input version, to be replayed later with WithReplay.
+//
+// The version identifies the form groups the answers were given to,
+// it must be incremented whenever form groups change in a way recorded answers must be reviewed.
+func WithRecord[T any](dst string, version int) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.record = &recordOptions{src: dst, version: version}
+		return ro
+	}
+}
+
+// WithReplay replays the answers recorded with WithRecord into src file (if it exists).
+//
+// Recorded answers are the form fields default values. When skip is true and the recorded version
+// is input version, the form isn't run (as with InteractiveNever), the answers being validated instead.
+// When versions differ, the form is run to review the answers.
+func WithReplay[T any](src string, version int, skip bool) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.replay = &recordOptions{skip: skip, src: src, version: version}
+		return ro
+	}
+}
+
+// recordOptions represents the options of WithRecord and WithReplay.
+type recordOptions struct {
+	skip    bool
+	src     string
+	version int
+}
+
+// readRecord reads the answers record at src.
+//
+// It returns false when src doesn't exist.
+func readRecord(src string) (answersRecord, bool, error) {
+	format, err := answersFormatFor(src)
+	if err != nil {
+		return answersRecord{}, false, err
+	}
+
+	var record answersRecord
+	if err := format.read(os.DirFS(filepath.Dir(src)), filepath.Base(src), &record); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return answersRecord{}, false, nil
+		}
+		return answersRecord{}, false, fmt.Errorf("read answers record: %w", err)
+	}
+	return record, true, nil
+}
+
+// writeRecord writes input config as answers record into dst with input version.
+func writeRecord(dst string, version int, config any) error {
+	format, err := answersFormatFor(dst)
+	if err != nil {
+		return err
+	}
+
+	// answers are represented with their JSON names, the same way they're decoded (see decodeAnswers)
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("marshal answers: %w", err)
+	}
+	var answers map[string]any
+	if err := json.Unmarshal(bytes, &answers); err != nil {
+		return fmt.Errorf("unmarshal answers: %w", err)
+	}
+
+	if err := format.write(dst, answersRecord{Answers: answers, Version: version}); err != nil {
+		return fmt.Errorf("write answers record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+)
+
+// WithReview sets whether a review page is shown at the end of Initialize interactive form (default false).
+//
+// The review page lists every answered field (by key, see huh.Input Key) of each page (group) not hidden,
+// the user can then confirm the answers or go back to a specific page to edit it
+// (the review page is shown again afterwards).
+func WithReview[T any](review bool) InitializeOption[T] {
+	return func(ro initializeOptions[T]) initializeOptions[T] {
+		ro.review = review
+		return ro
+	}
+}
+
+// reviewForm runs the review page until the user confirms its answers, running again the pages to edit.
+func (ro initializeOptions[T]) reviewForm(ctx context.Context, config *T) error {
+	for {
+		var rows [][]string
+		options := []huh.Option[int]{huh.NewOption("Confirm", -1)}
+		for i, formGroup := range ro.formGroups {
+			// groups are built again since they may depend on previous answers
+			group := formGroup(config)
+			if group == nil {
+				continue
+			}
+			fields, ok := groupFields(group)
+			if !ok {
+				continue // hidden group
+			}
+
+			page := "Page " + strconv.Itoa(i+1)
+			for position, field := range fields {
+				if _, ok := field.(*huh.Note); ok {
+					continue
+				}
+				name := field.GetKey()
+				if name == "" {
+					name = fmt.Sprintf("#%d", position)
+				}
+				rows = append(rows, []string{page, name, formatAnswer(field.GetValue())})
+			}
+			options = append(options, huh.NewOption("Edit "+strings.ToLower(page), i))
+		}
+
+		summary := table.New().
+			Border(lipgloss.NormalBorder()).
+			Headers("Page", "Field", "Answer").
+			Rows(rows...)
+
+		choice := -1
+		review := huh.NewForm(huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Review your answers").
+				Description(summary.String()).
+				Options(options...).
+				Value(&choice),
+		)).WithProgramOptions(ro.options...)
+		if err := ro.run(ctx, review); err != nil {
+			return err
+		}
+		if choice < 0 {
+			return nil
+		}
+
+		edit := huh.NewForm(ro.formGroups[choice](config)).
+			WithProgramOptions(ro.options...).
+			WithShowErrors(true)
+		if err := ro.run(ctx, edit); err != nil {
+			return err
+		}
+	}
+}
+
+// groupFields returns all fields of input group, or false when it's hidden.
+func groupFields(group *huh.Group) ([]huh.Field, bool) {
+	form := headlessForm(group)
+	if form.State != huh.StateNormal {
+		return nil, false
+	}
+
+	var fields []huh.Field
+	for {
+		field := form.GetFocusedField()
+		fields = append(fields, field)
+		if _ = form.NextField(); form.GetFocusedField() == field {
+			return fields, true
+		}
+	}
+}
+
+// formatAnswer returns the readable representation of a field value, slices values being separated with commas.
+func formatAnswer(value any) string {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return fmt.Sprint(value)
+	}
+
+	values := make([]string, 0, rv.Len())
+	for i := range rv.Len() {
+		values = append(values, fmt.Sprint(rv.Index(i).Interface()))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+// EscapeError is returned when a generation operation (write, chmod, mkdir, removal, etc.)
+// would escape the generation destination directory, either with '..' path elements
+// or by following a symbolic link pointing outside of it.
+type EscapeError struct {
+	// Err is the underlying error, if any.
+	Err error
+
+	// Path is the path escaping the destination directory.
+	Path string
+}
+
+var _ error = (*EscapeError)(nil) // ensure interface is implemented
+
+func (e *EscapeError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("path '%s' escapes destination directory", e.Path)
+	}
+	return fmt.Sprintf("path '%s' escapes destination directory: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *EscapeError) Unwrap() error {
+	return e.Err
+}
+
+// openRoot creates (if necessary) and opens the input destdir as an *os.Root
+// to confine all generation operations inside it.
+func openRoot(destdir string) (*os.Root, error) {
+	if err := os.MkdirAll(destdir, files.RwxRxRxRx); err != nil && !errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("mkdir: %w", err)
+	}
+	root, err := os.OpenRoot(destdir)
+	if err != nil {
+		return nil, fmt.Errorf("open root: %w", err)
+	}
+	return root, nil
+}
+
+// localName returns the input name (relative to a root) with OS separators,
+// returning an EscapeError if it isn't local to the root (i.e. absolute or with '..' escaping it).
+func localName(name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		return "", &EscapeError{Path: name}
+	}
+	return filepath.Clean(local), nil
+}
+
+// relativeName returns the input out path relative to the input root,
+// returning an EscapeError if it isn't inside the root.
+func relativeName(root *os.Root, out string) (string, error) {
+	rel, err := filepath.Rel(root.Name(), out)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", &EscapeError{Path: out, Err: err}
+	}
+	return rel, nil
+}
+
+// confined wraps the input *os.Root operation error on name (relative to root) into an EscapeError
+// when name escapes the root (see escapes), follow telling whether the operation follows name last element
+// in case it's a symbolic link (e.g. open or stat, but not remove or lstat).
+func confined(root *os.Root, name string, follow bool, err error) error {
+	if err != nil && escapes(root, name, follow) {
+		return &EscapeError{Path: name, Err: err}
+	}
+	return err
+}
+
+// maxSymlinks is the maximum number of symbolic links followed by escapes before giving up.
+const maxSymlinks = 255
+
+// escapes returns true if input name (relative to root) escapes the root, either with '..' path elements,
+// by being absolute or by following a symbolic link pointing outside of it (just like *os.Root operations forbid),
+// follow telling whether name last element is followed in case it's a symbolic link.
+//
+// Path elements are resolved one by one (with Lstat and Readlink) as long as they exist,
+// the remaining ones being checked lexically.
+func escapes(root *os.Root, name string, follow bool) bool {
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return true
+	}
+
+	var resolved []string // resolved path elements, all being existing directories inside root (or not existing)
+	pending := strings.Split(filepath.ToSlash(name), "/")
+	for links := 0; len(pending) > 0; {
+		element := pending[0]
+		pending = pending[1:]
+
+		switch element {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return true
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+
+		current := filepath.Join(append(resolved, element)...)
+		info, err := root.Lstat(current)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 || (len(pending) == 0 && !follow) {
+			resolved = append(resolved, element)
+			continue
+		}
+
+		if links++; links > maxSymlinks {
+			return false // too many links, the operation fails for another reason
+		}
+		target, err := root.Readlink(current)
+		if err != nil {
+			resolved = append(resolved, element)
+			continue
+		}
+		if filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+			return true
+		}
+		pending = append(strings.Split(filepath.ToSlash(target), "/"), pending...)
+	}
+	return false
+}
+
+// lexists returns true if input name exists inside root, without following it in case it's a symbolic link.
+//
+// Contrary to files.Exists, it returns true for dangling symbolic links.
+// It returns an EscapeError when name escapes the root (e.g. through a symbolic link pointing outside of it).
+func lexists(root *os.Root, name string) (bool, error) {
+	_, err := root.Lstat(name)
+	if err == nil {
+		return true, nil
+	}
+	if escapes(root, name, false) {
+		return false, &EscapeError{Path: name, Err: err}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// isSymlink returns true if input name inside root is a symbolic link (without following it).
+func isSymlink(root *os.Root, name string) bool {
+	info, err := root.Lstat(name)
+	return err == nil && info.Mode()&fs.ModeSymlink != 0
+}
@@ -0,0 +1,187 @@
+package engine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+func TestApplyTemplateEscape(t *testing.T) {
+	t.Run("error_write_through_symlinked_dir", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		outside := t.TempDir()
+		require.NoError(t, os.Symlink(outside, filepath.Join(destdir, "dir")))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, "template.txt"), []byte("content"), files.RwRR))
+		template := engine.Template[testconfig]{Globs: []string{"template.txt"}, Out: "dir/file.txt"}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		var escape *engine.EscapeError
+		require.ErrorAs(t, err, &escape)
+		assert.NoFileExists(t, filepath.Join(outside, "file.txt"))
+	})
+
+	t.Run("error_write_through_symlinks", func(t *testing.T) {
+		cases := map[string]func(destdir, outside string) string{
+			"absolute_inside": func(destdir, _ string) string { return filepath.Join(destdir, "sub") },
+			"relative_chain":  func(_, outside string) string { return "sub/../../" + filepath.Base(outside) },
+		}
+		for name, target := range cases {
+			t.Run(name, func(t *testing.T) {
+				// Arrange
+				parent := t.TempDir()
+				destdir := filepath.Join(parent, "dest")
+				outside := filepath.Join(parent, "outside")
+				require.NoError(t, os.MkdirAll(filepath.Join(destdir, "sub"), files.RwxRxRxRx))
+				require.NoError(t, os.MkdirAll(outside, files.RwxRxRxRx))
+				require.NoError(t, os.Symlink(target(destdir, outside), filepath.Join(destdir, "dir")))
+				require.NoError(t, os.WriteFile(filepath.Join(destdir, "template.txt"), []byte("content"), files.RwRR))
+				template := engine.Template[testconfig]{Globs: []string{"template.txt"}, Out: "dir/file.txt"}
+
+				// Act
+				err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+				// Assert
+				var escape *engine.EscapeError
+				require.ErrorAs(t, err, &escape)
+				assert.Equal(t, filepath.Join("dir", "file.txt"), escape.Path)
+				assert.NoFileExists(t, filepath.Join(outside, "file.txt"))
+			})
+		}
+	})
+
+	t.Run("error_not_escaping", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(destdir, "sub", "file.txt"), files.RwxRxRxRx))
+		require.NoError(t, os.Symlink("sub", filepath.Join(destdir, "dir")))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, "template.txt"), []byte("content"), files.RwRR))
+		template := engine.Template[testconfig]{Globs: []string{"template.txt"}, Out: "dir/file.txt"}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.Error(t, err) // file.txt is a directory
+		var escape *engine.EscapeError
+		assert.NotErrorAs(t, err, &escape)
+	})
+
+	t.Run("success_write_through_symlinks_inside", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(destdir, "sub", "nested"), files.RwxRxRxRx))
+		require.NoError(t, os.Symlink(filepath.Join("sub", "nested"), filepath.Join(destdir, "link")))
+		require.NoError(t, os.Symlink("link/..", filepath.Join(destdir, "dir")))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, "template.txt"), []byte("content"), files.RwRR))
+		template := engine.Template[testconfig]{Globs: []string{"template.txt"}, Out: "dir/file.txt"}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(destdir, "sub", "file.txt"))
+	})
+
+	t.Run("error_remove_through_symlinked_dir", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		outside := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(outside, "file.txt"), []byte("content"), files.RwRR))
+		require.NoError(t, os.Symlink(outside, filepath.Join(destdir, "dir")))
+		template := engine.Template[testconfig]{
+			Out:    "dir/file.txt",
+			Remove: func(testconfig) bool { return true },
+		}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		var escape *engine.EscapeError
+		assert.ErrorAs(t, err, &escape)
+		assert.FileExists(t, filepath.Join(outside, "file.txt"))
+	})
+
+	t.Run("error_symlink_target", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.yml", Symlink: "../outside.yml"}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		var escape *engine.EscapeError
+		assert.ErrorAs(t, err, &escape)
+	})
+}
+
+func TestGeneratorModulesEscape(t *testing.T) {
+	// Arrange
+	destdir := t.TempDir()
+	modules := func(config []testmodule) []testmodule { return config }
+	generator := engine.GeneratorModules(os.DirFS(destdir), modules,
+		[]engine.Template[testmodule]{{Out: ".golangci.yml", Symlink: ".golangci.yml"}})
+
+	// Act
+	err := generator(t.Context(), destdir, []testmodule{{directory: "../outside"}})
+
+	// Assert
+	assert.ErrorIs(t, err, engine.ErrFailedGeneration)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(destdir), "outside", ".golangci.yml"))
+}
+
+func TestExecuteTemplateRoot(t *testing.T) {
+	t.Run("error_escape", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		root, err := os.OpenRoot(destdir)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = root.Close() })
+
+		tmpl, err := template.New("template.txt").Parse("content")
+		require.NoError(t, err)
+		dest := filepath.Join(t.TempDir(), "file.txt")
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0, engine.ExecuteRoot(root))
+
+		// Assert
+		var escape *engine.EscapeError
+		require.ErrorAs(t, err, &escape)
+		assert.NoFileExists(t, dest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		root, err := os.OpenRoot(destdir)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = root.Close() })
+
+		tmpl, err := template.New("template.txt").Parse("content")
+		require.NoError(t, err)
+		dest := filepath.Join(destdir, "dir", "file.txt")
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0, engine.ExecuteRoot(root))
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+	})
+}
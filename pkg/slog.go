@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// AttrsLogger is an optional interface a Logger can implement to receive the structured attributes
+// the engine attaches to its own logs, alongside the printf-style message:
+//   - "out": generated (or removed) file path
+//   - "template": first template glob (see Template.Globs)
+//   - "module": module directory (see GeneratorModules)
+//   - "policy": generation policy (see GeneratePolicy)
+//   - "patch": applied patch name
+//   - "generator": index of the generator running (see Generate)
+//   - "duration": duration of a template generation or of a generator
+//
+// Loggers not implementing it only receive the printf-style messages.
+type AttrsLogger interface {
+	Logger
+
+	// LogAttrs logs input message (already formatted) with input level and attributes.
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+var _ AttrsLogger = (*slogLogger)(nil) // ensure interface is implemented
+
+// LoggerFromSlog returns a Logger writing to input *slog.Logger.
+//
+// It implements AttrsLogger, as such the engine structured attributes are given to the *slog.Logger.
+// A nil *slog.Logger falls back to slog.Default.
+func LoggerFromSlog(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// Debugf implements Logger.
+func (s *slogLogger) Debugf(format string, args ...any) {
+	s.logger.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Errorf implements Logger.
+func (s *slogLogger) Errorf(format string, args ...any) {
+	s.logger.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// Infof implements Logger.
+func (s *slogLogger) Infof(format string, args ...any) {
+	s.logger.Log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warnf implements Logger.
+func (s *slogLogger) Warnf(format string, args ...any) {
+	s.logger.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// LogAttrs implements AttrsLogger.
+func (s *slogLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	s.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// SlogFromLogger returns a *slog.Logger writing to input Logger.
+//
+// Records are given with their attributes to a Logger implementing AttrsLogger,
+// otherwise attributes are appended to the message as "key=value" pairs.
+// Groups are flattened into dotted keys (e.g. "group.key").
+func SlogFromLogger(logger Logger) *slog.Logger {
+	if logger == nil {
+		logger = &noopLogger{}
+	}
+	return slog.New(&loggerHandler{logger: logger})
+}
+
+type loggerHandler struct {
+	attrs  []slog.Attr
+	logger Logger
+	prefix string
+}
+
+var _ slog.Handler = (*loggerHandler)(nil) // ensure interface is implemented
+
+// Enabled implements slog.Handler.
+//
+// Levels filtering is up to the wrapped Logger.
+func (*loggerHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler.
+func (h *loggerHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+record.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, h.qualify(attr)...)
+		return true
+	})
+
+	if structured, ok := h.logger.(AttrsLogger); ok {
+		structured.LogAttrs(ctx, record.Level, record.Message, attrs...)
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString(record.Message)
+	for _, attr := range attrs {
+		builder.WriteString(" " + attr.String())
+	}
+	logLevel(h.logger, record.Level, "%s", builder.String())
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	clone.attrs = append(clone.attrs, h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, h.qualify(attr)...)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// qualify returns input attribute (flattened in case it's a group) with its key prefixed by the handler groups.
+func (h *loggerHandler) qualify(attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		attr.Key = h.prefix + attr.Key
+		return []slog.Attr{attr}
+	}
+
+	group := *h
+	if attr.Key != "" {
+		group.prefix = h.prefix + attr.Key + "."
+	}
+	attrs := make([]slog.Attr, 0, len(attr.Value.Group()))
+	for _, sub := range attr.Value.Group() {
+		attrs = append(attrs, group.qualify(sub)...)
+	}
+	return attrs
+}
+
+// logAttrs logs input message with input level through the configured logger,
+// giving it the structured attributes in case it implements AttrsLogger.
+func logAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr, format string, args ...any) {
+	logger := GetLogger()
+	if structured, ok := logger.(AttrsLogger); ok {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		structured.LogAttrs(ctx, level, fmt.Sprintf(format, args...), attrs...)
+		return
+	}
+	logLevel(logger, level, format, args...)
+}
+
+// logLevel logs input message with the Logger method associated to input level.
+func logLevel(logger Logger, level slog.Level, format string, args ...any) {
+	switch {
+	case level < slog.LevelInfo:
+		logger.Debugf(format, args...)
+	case level < slog.LevelWarn:
+		logger.Infof(format, args...)
+	case level < slog.LevelError:
+		logger.Warnf(format, args...)
+	default:
+		logger.Errorf(format, args...)
+	}
+}
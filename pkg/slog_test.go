@@ -0,0 +1,107 @@
+package engine_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+func TestLoggerFromSlog(t *testing.T) {
+	newLogger := func(buf *bytes.Buffer) *slog.Logger {
+		return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return attr
+			},
+		}))
+	}
+
+	t.Run("success_printf", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		logger := engine.LoggerFromSlog(newLogger(&buf))
+
+		// Act
+		logger.Warnf("some %s", "message")
+
+		// Assert
+		assert.JSONEq(t, `{"level":"WARN","msg":"some message"}`, buf.String())
+	})
+
+	t.Run("success_engine_attributes", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		engine.Configure(engine.WithLogger(engine.LoggerFromSlog(newLogger(&buf))))
+		t.Cleanup(func() { engine.Configure() })
+
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			GeneratePolicy: engine.PolicyCreateOnce,
+			Globs:          []string{"file.txt.tmpl"},
+			Out:            "file.txt",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("manual"), files.RwRR))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		expected := map[string]any{
+			"level":    "INFO",
+			"msg":      "not generating 'file.txt' since it already exists (or was modified manually)",
+			"out":      filepath.Join(destdir, template.Out),
+			"policy":   "create_once",
+			"template": "file.txt.tmpl",
+		}
+		assert.Equal(t, expected, record)
+	})
+}
+
+func TestSlogFromLogger(t *testing.T) {
+	t.Run("success_printf_logger", func(t *testing.T) {
+		// Arrange
+		var buf strings.Builder
+		logger := engine.SlogFromLogger(engine.NewTestLogger(&buf))
+
+		// Act
+		logger.With("module", "apps/api").WithGroup("file").Info("generated", "out", "README.md", slog.Group("size", "bytes", 10))
+
+		// Assert
+		assert.Equal(t, "generated module=apps/api file.out=README.md file.size.bytes=10", buf.String())
+	})
+
+	t.Run("success_attrs_logger", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		logger := engine.SlogFromLogger(engine.LoggerFromSlog(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return attr
+			},
+		}))))
+
+		// Act
+		logger.Error("failed", "out", "README.md")
+
+		// Assert
+		assert.Equal(t, "level=ERROR msg=failed out=README.md\n", buf.String())
+	})
+}
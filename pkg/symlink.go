@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+// applySymlink creates (or updates) the relative symbolic link name (relative to root)
+// pointing to tmpl.Symlink (relative to root too).
+//
+// An existing symbolic link is updated unless the policy is PolicyNever or PolicyCreateOnce,
+// an existing file is replaced according to ShouldGenerate.
+func applySymlink[T any](ctx context.Context, root *os.Root, name string, tmpl Template[T], policy GeneratePolicy, attrs []slog.Attr) error {
+	if path.IsAbs(tmpl.Symlink) || filepath.IsAbs(tmpl.Symlink) {
+		return fmt.Errorf("symlink target '%s' must be relative", tmpl.Symlink)
+	}
+	symlink, err := localName(tmpl.Symlink)
+	if err != nil {
+		return fmt.Errorf("symlink target: %w", err)
+	}
+	target, err := filepath.Rel(filepath.Dir(name), symlink)
+	if err != nil {
+		return fmt.Errorf("relative symlink target: %w", err)
+	}
+	out := filepath.Join(root.Name(), name)
+
+	if isSymlink(root, name) {
+		if current, err := root.Readlink(name); err == nil && current == target {
+			logAttrs(ctx, slog.LevelDebug, attrs, "not linking '%s' since it already points to '%s'", tmpl.Out, tmpl.Symlink)
+			emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: fmt.Sprintf("it already points to '%s'", tmpl.Symlink)})
+			return nil
+		}
+		if policy == PolicyNever || policy == PolicyCreateOnce {
+			logAttrs(ctx, slog.LevelInfo, attrs, "not linking '%s' since it already exists", tmpl.Out)
+			emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "it already exists"})
+			return nil
+		}
+	} else {
+		ok, err := ShouldGenerate(out, policy)
+		if err != nil {
+			return fmt.Errorf("should generate: %w", err)
+		}
+		if !ok {
+			logAttrs(ctx, slog.LevelInfo, attrs, "not linking '%s' since it already exists (or was modified manually)", tmpl.Out)
+			emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "it already exists (or was modified manually)"})
+			return nil
+		}
+	}
+
+	logAttrs(ctx, slog.LevelDebug, attrs, "linking '%s' to '%s'", tmpl.Out, tmpl.Symlink)
+	if err := root.MkdirAll(filepath.Dir(name), files.RwxRxRxRx); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("mkdir: %w", confined(root, name, false, err))
+	}
+	if err := root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove '%s': %w", tmpl.Out, confined(root, name, false, err))
+	}
+	if err := root.Symlink(target, name); err != nil {
+		return fmt.Errorf("symlink: %w", confined(root, name, false, err))
+	}
+	checksums().Delete(out)
+	emit(ctx, Event{Kind: EventFileWritten, Path: out})
+	return nil
+}
@@ -0,0 +1,200 @@
+package engine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	engine "github.com/kickr-dev/engine/pkg"
+	"github.com/kickr-dev/engine/pkg/files"
+)
+
+func TestApplyTemplateSymlink(t *testing.T) {
+	t.Run("error_absolute_target", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.yml", Symlink: "/etc/passwd"}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		assert.ErrorContains(t, err, "must be relative")
+	})
+
+	t.Run("success_create", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "apps/api/.golangci.yml", Symlink: ".golangci.yml"}
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		target, err := os.Readlink(filepath.Join(destdir, "apps", "api", ".golangci.yml"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("..", "..", ".golangci.yml"), target)
+	})
+
+	t.Run("success_update_link", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.yml", Symlink: "target.yml"}
+		require.NoError(t, os.Symlink("other.yml", filepath.Join(destdir, template.Out)))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		target, err := os.Readlink(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "target.yml", target)
+	})
+
+	t.Run("success_keep_link_create_once", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{GeneratePolicy: engine.PolicyCreateOnce, Out: "link.yml", Symlink: "target.yml"}
+		require.NoError(t, os.Symlink("other.yml", filepath.Join(destdir, template.Out)))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		target, err := os.Readlink(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "other.yml", target)
+	})
+
+	t.Run("success_keep_manual_file", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.yml", Symlink: "target.yml"}
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("manual"), files.RwRR))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "manual", string(content))
+	})
+
+	t.Run("success_replace_generated_file", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.yml", Symlink: "target.yml"}
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("# Code generated by kickr; DO NOT EDIT."), files.RwRR))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		target, err := os.Readlink(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "target.yml", target)
+	})
+
+	t.Run("success_remove_dangling", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.yml", Remove: func(testconfig) bool { return true }}
+		require.NoError(t, os.Symlink("missing.yml", filepath.Join(destdir, template.Out)))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		_, err = os.Lstat(filepath.Join(destdir, template.Out))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("success_not_generated_through_link", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		outside := filepath.Join(t.TempDir(), "outside.txt")
+		require.NoError(t, os.WriteFile(outside, []byte("# Code generated by kickr; DO NOT EDIT."), files.RwRR))
+
+		template := engine.Template[testconfig]{Globs: []string{"file.txt" + engine.TmplExtension}, Out: "file.txt"}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte("content"), files.RwRR))
+		require.NoError(t, os.Symlink(outside, filepath.Join(destdir, template.Out)))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(outside)
+		require.NoError(t, err)
+		assert.Equal(t, "# Code generated by kickr; DO NOT EDIT.", string(content))
+	})
+
+	t.Run("error_patch_link", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{Out: "link.txt", Patches: []string{"file.patch"}}
+		require.NoError(t, os.Symlink("target.txt", filepath.Join(destdir, template.Out)))
+
+		// Act
+		err := engine.ApplyPatches(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		assert.ErrorContains(t, err, "symbolic links can't be patched")
+	})
+}
+
+func TestGeneratorModulesSymlink(t *testing.T) {
+	// Arrange
+	destdir := t.TempDir()
+	modules := func(config []testmodule) []testmodule { return config }
+	generator := engine.GeneratorModules(os.DirFS(destdir), modules,
+		[]engine.Template[testmodule]{{Out: ".golangci.yml", Symlink: ".golangci.yml"}})
+
+	// Act
+	err := generator(t.Context(), destdir, []testmodule{{directory: "apps/api"}, {directory: "libs"}})
+
+	// Assert
+	require.NoError(t, err)
+	target, err := os.Readlink(filepath.Join(destdir, "apps", "api", ".golangci.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("..", "..", ".golangci.yml"), target)
+	target, err = os.Readlink(filepath.Join(destdir, "libs", ".golangci.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("..", ".golangci.yml"), target)
+}
+
+func TestExecuteTemplateSymlink(t *testing.T) {
+	// Arrange
+	destdir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("outside"), files.RwRR))
+	dest := filepath.Join(destdir, "file.txt")
+	require.NoError(t, os.Symlink(outside, dest))
+
+	tmpl, err := template.New("template.txt").Parse("content")
+	require.NoError(t, err)
+
+	// Act
+	err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0)
+
+	// Assert
+	require.NoError(t, err)
+	info, err := os.Lstat(dest)
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+	content, err := os.ReadFile(outside)
+	require.NoError(t, err)
+	assert.Equal(t, "outside", string(content))
+}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/bluekeyes/go-gitdiff/gitdiff"
@@ -19,14 +22,23 @@ import (
 
 // GeneratorTemplates is a simple generator taking as input a filesystem and all templates to apply.
 //
+// All writes, chmods, mkdirs and removals are confined to destdir (see EscapeError).
+//
 // Errors encountered during templates generation are logged, in that case a final error being ErrFailedGeneration is returned.
 func GeneratorTemplates[T any](fsys fs.FS, templates []Template[T]) Generator[T] {
-	return func(_ context.Context, destdir string, config T) error {
+	return func(ctx context.Context, destdir string, config T) error {
+		root, err := openRoot(destdir)
+		if err != nil {
+			return err
+		}
+		defer root.Close()
+
 		var errcount int
 		for _, tmpl := range templates {
-			if err := ApplyTemplate(fsys, destdir, tmpl, config); err != nil {
+			if err := applyTemplate(ctx, fsys, root, "", tmpl, config); err != nil {
 				errcount++
-				GetLogger().Errorf("failed to generate '%s': %v", path.Base(tmpl.Out), err)
+				logAttrs(ctx, slog.LevelError, templateAttrs(ctx, root, "", tmpl),
+					"failed to generate '%s': %v", path.Base(tmpl.Out), err)
 			}
 		}
 		if errcount > 0 {
@@ -42,17 +54,26 @@ func GeneratorTemplates[T any](fsys fs.FS, templates []Template[T]) Generator[T]
 //
 // Each Template.Out is relative to each module where it will be generated
 // and Template.Remove is up to the characteristics of a given module.
+// Template.Symlink stays relative to the repository root to link modules files to a shared root file.
+//
+// All writes, chmods, mkdirs and removals are confined to destdir (the repository root),
+// a module whose Module.Dir isn't local to destdir fails with an EscapeError.
 //
 // Errors encountered during templates generation are logged, in that case a final error being ErrFailedGeneration is returned.
 func GeneratorModules[T any, M Module](fsys fs.FS, modules func(config T) []M, templates []Template[M]) Generator[T] {
-	generator := GeneratorTemplates(fsys, templates)
-
 	return func(ctx context.Context, destdir string, config T) error {
+		root, err := openRoot(destdir)
+		if err != nil {
+			return err
+		}
+		defer root.Close()
+
 		var failed bool
 		for _, module := range modules(config) {
-			if err := generator(ctx, filepath.Join(destdir, module.Dir()), module); err != nil {
+			if err := applyModule(ctx, fsys, root, module, templates); err != nil {
 				failed = true
-				GetLogger().Errorf("failed to generate '%s': %v", module.Dir(), err)
+				logAttrs(ctx, slog.LevelError, []slog.Attr{slog.String("module", module.Dir())},
+					"failed to generate '%s': %v", module.Dir(), err)
 			}
 		}
 		if failed {
@@ -62,110 +83,266 @@ func GeneratorModules[T any, M Module](fsys fs.FS, modules func(config T) []M, t
 	}
 }
 
+// applyModule applies all input templates inside the module directory (relative to root).
+func applyModule[M Module](ctx context.Context, fsys fs.FS, root *os.Root, module M, templates []Template[M]) error {
+	dir, err := localName(module.Dir())
+	if err != nil {
+		return fmt.Errorf("module directory: %w", err)
+	}
+
+	var errcount int
+	for _, tmpl := range templates {
+		if err := applyTemplate(ctx, fsys, root, dir, tmpl, module); err != nil {
+			errcount++
+			logAttrs(ctx, slog.LevelError, templateAttrs(ctx, root, dir, tmpl),
+				"failed to generate '%s': %v", path.Base(tmpl.Out), err)
+		}
+	}
+	if errcount > 0 {
+		return ErrFailedGeneration
+	}
+	return nil
+}
+
 // ApplyTemplate writes or deletes an input Template with associated data.
+//
+// All writes, chmods, mkdirs and removals are confined to destdir (see EscapeError).
 func ApplyTemplate[T any](fsys fs.FS, destdir string, tmpl Template[T], config T) error {
+	// validate out before creating destdir
+	if _, err := filepath.Localize(tmpl.Out); err != nil {
+		return fmt.Errorf("localize path: %w", err)
+	}
+
+	root, err := openRoot(destdir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	return applyTemplate(context.Background(), fsys, root, "", tmpl, config)
+}
+
+// applyTemplate writes or deletes an input Template with associated data inside dir (relative to root).
+func applyTemplate[T any](ctx context.Context, fsys fs.FS, root *os.Root, dir string, tmpl Template[T], config T) error {
 	// force out localization since generation is always done on current fs
 	out, err := filepath.Localize(tmpl.Out)
 	if err != nil {
 		return fmt.Errorf("localize path: %w", err)
 	}
-	out = filepath.Join(destdir, out)
+	name := filepath.Join(dir, out)
+	out = filepath.Join(root.Name(), name)
+	attrs := templateAttrs(ctx, root, dir, tmpl)
 
 	// remove file in case result is asking it
 	if tmpl.Remove != nil && tmpl.Remove(config) {
-		if !files.Exists(out) {
+		exists, err := lexists(root, name)
+		if err != nil {
+			return fmt.Errorf("remove '%s': %w", tmpl.Out, err)
+		}
+		if !exists {
 			return nil
 		}
 
-		GetLogger().Debugf("removing '%s'", tmpl.Out)
-		if err := os.RemoveAll(out); err != nil {
-			return fmt.Errorf("remove '%s': %w", tmpl.Out, err)
+		logAttrs(ctx, slog.LevelDebug, attrs, "removing '%s'", tmpl.Out)
+		if err := root.RemoveAll(name); err != nil {
+			return fmt.Errorf("remove '%s': %w", tmpl.Out, confined(root, name, false, err))
 		}
+		checksums().Delete(out)
+		emit(ctx, Event{Kind: EventFileRemoved, Path: out})
 		return nil
 	}
 
+	policy := tmpl.GeneratePolicy
+	if tmpl.GeneratePolicyFunc != nil {
+		policy = tmpl.GeneratePolicyFunc(config)
+	}
+	attrs = append(attrs, slog.String("policy", policy.String()))
+	if tmpl.Symlink != "" {
+		return applySymlink(ctx, root, name, tmpl, policy, attrs)
+	}
+	tmplFuncs, data := templateFuncs(tmpl, config), templateData(tmpl, config)
+
 	// avoid generating file if it already exists or something else
-	ok, err := ShouldGenerate(out, tmpl.GeneratePolicy)
+	ok, err := ShouldGenerate(out, policy)
 	if err != nil {
 		return fmt.Errorf("should generate: %w", err)
 	}
+	owner, foreign := "", false
+	if ok && tmpl.Notice != "" && policy != PolicyAlways && !Forced() {
+		owner, foreign = foreignGenerator(out, tmpl.Notice)
+	}
 	switch {
+	case policy == PolicyNever:
+		logAttrs(ctx, slog.LevelDebug, attrs, "not generating '%s' since its policy is to never generate it", tmpl.Out)
+		emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "its policy is to never generate it"})
 	case !ok:
-		GetLogger().Infof("not generating '%s' since it already exists (or was modified manually)", tmpl.Out)
+		logAttrs(ctx, slog.LevelInfo, attrs, "not generating '%s' since it already exists (or was modified manually)", tmpl.Out)
+		emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "it already exists (or was modified manually)"})
+	case foreign:
+		logAttrs(ctx, slog.LevelInfo, attrs, "not generating '%s' since it's owned by generator '%s'", tmpl.Out, owner)
+		emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: fmt.Sprintf("it's owned by generator '%s'", owner)})
 	case len(tmpl.Globs) == 0:
-		GetLogger().Warnf("empty template 'globs', skipping '%s' generation", tmpl.Out)
+		logAttrs(ctx, slog.LevelWarn, attrs, "empty template 'globs', skipping '%s' generation", tmpl.Out)
+		emit(ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "empty template 'globs'"})
 	default:
-		GetLogger().Debugf("generating '%s'", tmpl.Out)
+		logAttrs(ctx, slog.LevelDebug, attrs, "generating '%s'", tmpl.Out)
+		start := time.Now()
 		tt, err := template.New(path.Base(tmpl.Globs[0])).
 			Funcs(sprig.FuncMap()).
 			Funcs(FuncMap()).
 			Funcs(funcs()).
+			Funcs(tmplFuncs).
 			Delims(tmpl.StartDelim, tmpl.EndDelim).
 			ParseFS(fsys, tmpl.Globs...)
 		if err != nil {
 			return fmt.Errorf("parse template file(s): %w", err)
 		}
-		if err := ExecuteTemplate(tt, config, out, tmpl.EmptyPolicy, tmpl.Mode); err != nil {
+		(&includeFuncs{funcs: tmplFuncs}).bind(tt.Funcs(destFuncs(ctx, root, dir)))
+		if err := ExecuteTemplate(tt, data, out, tmpl.EmptyPolicy, tmpl.Mode,
+			ExecuteEmptyFunc(tmpl.EmptyFunc),
+			ExecuteFormat(tmpl.Format),
+			ExecuteNotice(tmpl.Notice),
+			ExecuteRoot(root),
+			ExecuteContext(ctx)); err != nil {
 			return fmt.Errorf("template execute: %w", err)
 		}
+		logAttrs(ctx, slog.LevelDebug, append(attrs, slog.Duration("duration", time.Since(start))), "generated '%s'", tmpl.Out)
 	}
 
 	if len(tmpl.Patches) > 0 {
-		GetLogger().Infof("applying patches on '%s'", path.Base(out))
-		return ApplyPatches(fsys, destdir, tmpl, config)
+		logAttrs(ctx, slog.LevelInfo, attrs, "applying patches on '%s'", path.Base(out))
+		return applyPatches(ctx, fsys, root, dir, name, tmpl, tmplFuncs, data)
 	}
 	return nil
 }
 
+// templateAttrs returns the structured logging attributes of input template generated inside dir (relative to root).
+func templateAttrs[T any](ctx context.Context, root *os.Root, dir string, tmpl Template[T]) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 5)
+	attrs = append(attrs, slog.String("out", filepath.Join(root.Name(), dir, filepath.FromSlash(tmpl.Out))))
+	if len(tmpl.Globs) > 0 {
+		attrs = append(attrs, slog.String("template", tmpl.Globs[0]))
+	}
+	if dir != "" {
+		attrs = append(attrs, slog.String("module", filepath.ToSlash(dir)))
+	}
+	if index := indexFrom(ctx); index >= 0 {
+		attrs = append(attrs, slog.Int("generator", index))
+	}
+	return attrs
+}
+
+// templateFuncs returns the functions specific to input template for input configuration (see Template.FuncMap).
+func templateFuncs[T any](tmpl Template[T], config T) template.FuncMap {
+	if tmpl.FuncMap == nil {
+		return nil
+	}
+	return tmpl.FuncMap(config)
+}
+
+// templateData returns the value given to input template execution for input configuration (see Template.Data).
+func templateData[T any](tmpl Template[T], config T) any {
+	if tmpl.Data == nil {
+		return config
+	}
+	return tmpl.Data(config)
+}
+
 // ApplyPatches apply patches defined in input tmpl.
 // Each patch is templatized using Go template and then patched on provided tmpl file.
 //
 // It's the continuance function of ApplyTemplate (which only generates - if necessary - the initial template).
+//
+// Patches are applied on the normalized file content (LF line endings without byte order mark)
+// and tmpl.Format is applied on the result. When tmpl.Format doesn't specify them,
+// the initial file line endings and byte order mark are kept.
+//
+// When data is a T, it's given to tmpl.FuncMap and mapped with tmpl.Data (if provided) just like with ApplyTemplate,
+// otherwise it's given as is to patches execution and tmpl.FuncMap receives T zero value.
+//
+// All writes are confined to destdir (see EscapeError).
 func ApplyPatches[T any](fsys fs.FS, destdir string, tmpl Template[T], data any) error {
 	// force out localization since generation is always done on current fs
 	out, err := filepath.Localize(tmpl.Out)
 	if err != nil {
 		return fmt.Errorf("localize path: %w", err)
 	}
-	out = filepath.Join(destdir, out)
+
+	root, err := openRoot(destdir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	config, ok := data.(T)
+	if ok {
+		data = templateData(tmpl, config)
+	}
+	return applyPatches(context.Background(), fsys, root, "", out, tmpl, templateFuncs(tmpl, config), data)
+}
+
+// applyPatches apply patches defined in input tmpl on name (relative to root),
+// dir being the directory (relative to root) the template is generated in
+// and tmplFuncs the functions specific to tmpl (see templateFuncs).
+func applyPatches[T any](ctx context.Context, fsys fs.FS, root *os.Root, dir, name string, tmpl Template[T], tmplFuncs template.FuncMap, data any) error {
+	// avoid writing through a symbolic link, possibly outside destdir
+	if isSymlink(root, name) {
+		return fmt.Errorf("patch '%s': symbolic links can't be patched", tmpl.Out)
+	}
 
 	apply := func(diff *gitdiff.File) error {
-		file, err := os.OpenFile(out, os.O_RDWR|os.O_CREATE, files.RwRR)
+		file, err := root.OpenFile(name, os.O_RDWR|os.O_CREATE, files.RwRR)
 		if err != nil {
-			return fmt.Errorf("open file: %w", err)
+			return fmt.Errorf("open file: %w", confined(root, name, true, err))
 		}
 		defer file.Close()
 
+		initial, err := io.ReadAll(file)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+
+		// patches are applied on normalized content (no BOM and LF line endings) to handle CRLF files
+		detected, normalized := normalize(initial)
+
 		var output bytes.Buffer
-		if err := gitdiff.Apply(&output, file, diff); err != nil {
+		if err := gitdiff.Apply(&output, bytes.NewReader(normalized), diff); err != nil {
 			return fmt.Errorf("apply diff: %w", err)
 		}
+		content := tmpl.Format.merge(detected).Apply(output.Bytes())
 
 		// truncate manually (instead of os.O_TRUNC) and after apply because patching needs the initial content
-		if err := file.Truncate(int64(output.Len())); err != nil {
+		if err := file.Truncate(int64(len(content))); err != nil {
 			return fmt.Errorf("truncate file: %w", err)
 		}
-		if _, err := file.WriteAt(output.Bytes(), 0); err != nil {
+		if _, err := file.WriteAt(content, 0); err != nil {
 			return fmt.Errorf("write file: %w", err)
 		}
+		// the checksum is only updated when the engine owns the patched content (i.e. it wasn't modified manually)
+		out := filepath.Join(root.Name(), name)
+		if sum, ok := checksums().Sum(out); ok && sum == checksum(initial) {
+			checksums().Set(out, content)
+		}
 		return nil
 	}
 
 	errs := make([]error, 0, len(tmpl.Patches))
 	for _, patch := range tmpl.Patches {
 		patchname := path.Base(patch)
-		GetLogger().Debugf("applying patch file '%s'", patchname)
+		attrs := []slog.Attr{slog.String("out", filepath.Join(root.Name(), name)), slog.String("patch", patchname)}
+		logAttrs(ctx, slog.LevelDebug, attrs, "applying patch file '%s'", patchname)
 
 		tt, err := template.New(patchname).
 			Funcs(sprig.FuncMap()).
 			Funcs(FuncMap()).
 			Funcs(funcs()).
+			Funcs(tmplFuncs).
 			Delims(tmpl.StartDelim, tmpl.EndDelim).
 			ParseFS(fsys, patch)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("parse template patch '%s': %w", patchname, err))
 			continue
 		}
+		(&includeFuncs{funcs: tmplFuncs}).bind(tt.Funcs(destFuncs(ctx, root, dir)))
 
 		var buffer bytes.Buffer
 		if err := tt.Execute(&buffer, data); err != nil {
@@ -179,12 +356,17 @@ func ApplyPatches[T any](fsys fs.FS, destdir string, tmpl Template[T], data any)
 			continue
 		}
 
+		applied := true
 		for index, diff := range diffs {
-			GetLogger().Debugf("applying diff number '%d' of '%s'", index, patchname)
+			logAttrs(ctx, slog.LevelDebug, attrs, "applying diff number '%d' of '%s'", index, patchname)
 			if err := apply(diff); err != nil {
+				applied = false
 				errs = append(errs, fmt.Errorf("apply diff number '%d' of '%s': %w", index, patchname, err))
 			}
 		}
+		if applied {
+			emit(ctx, Event{Kind: EventPatchApplied, Patch: patchname, Path: filepath.Join(root.Name(), name)})
+		}
 	}
 	return errors.Join(errs...)
 }
@@ -192,32 +374,85 @@ func ApplyPatches[T any](fsys fs.FS, destdir string, tmpl Template[T], data any)
 // ExecuteTemplate runs tmpl.ExecuteTemplate with input data and write result into given out.
 //
 // When ExecuteTemplate is called, it truncates out in case it already exists and reevaluate its rights.
+// In case out is a symbolic link, the link itself is replaced by the generated file (its target is left untouched).
+//
+// With PolicyRemove, the result isn't written (and out is removed if it exists) when it's considered empty
+// according to the EmptyFunc associated to out (see EmptyFuncFor and ExecuteEmptyFunc).
 //
 // The input mode sets the requested file mode for the generated file (e.g. files.RwRR, files.RwxRxRxRx),
 // defaulting to files.RwRR when not provided.
 //
 // The system umask (see files.Umask) is always applied on top (mode &^ umask, no-op on non-compatible platforms).
-func ExecuteTemplate(tmpl *template.Template, data any, out string, policy EmptyPolicy, mode os.FileMode) error {
+//
+// All writes are confined to the root given with ExecuteRoot or, by default, to out parent directory (see EscapeError).
+//
+// Additional behaviors can be given with ExecuteOption functions (e.g. ExecuteNotice).
+func ExecuteTemplate(tmpl *template.Template, data any, out string, policy EmptyPolicy, mode os.FileMode, opts ...ExecuteOption) error {
+	eo := newExecuteOptions(opts...)
+
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("template execution: %w", err)
 	}
 
-	if ok := IsEmpty(buf.Bytes(), policy); ok {
-		base := filepath.Base(out)
-		if !files.Exists(out) {
-			GetLogger().Debugf("not generating '%s' since it would be empty", base)
+	content := buf.Bytes()
+	if eo.notice != "" {
+		content = InjectNotice(content, out, eo.notice)
+	}
+	emit(eo.ctx, Event{Kind: EventTemplateRendered, Path: out})
+	attrs := []slog.Attr{slog.String("out", out)}
+
+	empty := eo.empty
+	if empty == nil {
+		empty = EmptyFuncFor(out)
+	}
+	isEmpty := policy != PolicyKeep && empty(content)
+
+	root := eo.root
+	if root == nil {
+		// confine writes to out parent directory when no root is given
+		dir := filepath.Dir(out)
+		if isEmpty && !files.Exists(dir) {
+			logAttrs(eo.ctx, slog.LevelDebug, attrs, "not generating '%s' since it would be empty", filepath.Base(out))
+			emit(eo.ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "it would be empty"})
 			return nil
 		}
-		GetLogger().Debugf("removing '%s' since it's empty", base)
-		if err := os.RemoveAll(out); err != nil {
+		parent, err := openRoot(dir)
+		if err != nil {
+			return err
+		}
+		defer parent.Close()
+		root = parent
+	}
+	name, err := relativeName(root, out)
+	if err != nil {
+		return err
+	}
+
+	if isEmpty {
+		base := filepath.Base(out)
+		exists, err := lexists(root, name)
+		if err != nil {
 			return fmt.Errorf("remove '%s': %w", base, err)
 		}
+		if !exists {
+			logAttrs(eo.ctx, slog.LevelDebug, attrs, "not generating '%s' since it would be empty", base)
+			emit(eo.ctx, Event{Kind: EventFileSkipped, Path: out, Reason: "it would be empty"})
+			return nil
+		}
+		logAttrs(eo.ctx, slog.LevelDebug, attrs, "removing '%s' since it's empty", base)
+		if err := root.RemoveAll(name); err != nil {
+			return fmt.Errorf("remove '%s': %w", base, confined(root, name, false, err))
+		}
+		checksums().Delete(out)
+		emit(eo.ctx, Event{Kind: EventFileRemoved, Path: out})
 		return nil
 	}
 
-	if err := os.MkdirAll(filepath.Dir(out), files.RwxRxRxRx); err != nil && !errors.Is(err, fs.ErrExist) {
-		return fmt.Errorf("mkdir: %w", err)
+	content = eo.format.Apply(content)
+
+	if err := root.MkdirAll(filepath.Dir(name), files.RwxRxRxRx); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("mkdir: %w", confined(root, name, false, err))
 	}
 
 	// affect the right rights to out file, honoring the system umask
@@ -227,13 +462,20 @@ func ExecuteTemplate(tmpl *template.Template, data any, out string, policy Empty
 	}
 	requested &^= files.Umask()
 
-	file, err := os.OpenFile(out, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, requested)
+	// replace symbolic links instead of writing through them, possibly outside destination directory
+	if isSymlink(root, name) {
+		if err := root.Remove(name); err != nil {
+			return fmt.Errorf("remove symlink: %w", confined(root, name, false, err))
+		}
+	}
+
+	file, err := root.OpenFile(name, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, requested)
 	if err != nil {
-		return fmt.Errorf("open file: %w", err)
+		return fmt.Errorf("open file: %w", confined(root, name, true, err))
 	}
 	defer file.Close()
 
-	if _, err := file.Write(buf.Bytes()); err != nil {
+	if _, err := file.Write(content); err != nil {
 		return fmt.Errorf("write file: %w", err)
 	}
 
@@ -241,5 +483,7 @@ func ExecuteTemplate(tmpl *template.Template, data any, out string, policy Empty
 	if err := file.Chmod(requested); err != nil {
 		return fmt.Errorf("chmod: %w", err)
 	}
+	checksums().Set(out, content)
+	emit(eo.ctx, Event{Kind: EventFileWritten, Path: out})
 	return nil
 }
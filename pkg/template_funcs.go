@@ -2,29 +2,238 @@ package engine
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"go/token"
+	"io/fs"
 	"net/url"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"dario.cat/mergo"
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/goccy/go-yaml"
+	toml "github.com/pelletier/go-toml/v2"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/kickr-dev/engine/pkg/internal/versions"
 )
 
 // FuncMap returns a minimal template.FuncMap.
 //
 // It can be extended with MergeMaps.
+//
+// "include" (executes a named template and returns its result, e.g. to pipe it into "nindent")
+// and "tpl" (renders a string as a template with input data) are only available in templates and patches
+// executed by ApplyTemplate and ApplyPatches, since they're bound to the executed template set.
+//
+// The same goes for read-only filesystem functions "readFile", "fileExists", "glob" and "listDir",
+// scoped to the generation destination directory (or the module directory with GeneratorModules).
+// Paths escaping it are rejected with an EscapeError and each access is emitted as an EventFileRead.
 func FuncMap() template.FuncMap {
 	return template.FuncMap{
-		"cutAfter": cutAfter,
-		"map":      mergeMaps,
-		"toSlug":   ToSlug,
-		"toQuery":  toQuery,
-		"toYaml":   toYAML,
+		"cutAfter":     cutAfter,
+		"fileExists":   func(string) (bool, error) { return false, errUnboundTemplate },
+		"fromToml":     fromTOML,
+		"fromYaml":     fromYAML,
+		"glob":         func(string) ([]string, error) { return nil, errUnboundTemplate },
+		"include":      func(string, any) (string, error) { return "", errUnboundTemplate },
+		"listDir":      func(string) ([]string, error) { return nil, errUnboundTemplate },
+		"map":          mergeMaps,
+		"mustToYaml":   mustToYAML,
+		"notice":       Notice,
+		"readFile":     func(string) (string, error) { return "", errUnboundTemplate },
+		"semverBump":   semverBump,
+		"semverLatest": semverLatest,
+		"semverTags":   semverTags,
+		"toDnsLabel":   ToDNSLabel,
+		"toEnvVar":     ToEnvVar,
+		"toGoPackage":  ToGoPackage,
+		"toNpmPackage": ToNpmPackage,
+		"toPrettyJson": toPrettyJSON,
+		"toSlug":       ToSlug,
+		"toToml":       toTOML,
+		"toQuery":      toQuery,
+		"toYaml":       toYAML,
+		"tpl":          func(string, any) (string, error) { return "", errUnboundTemplate },
+	}
+}
+
+// maxIncludeDepth is the maximum number of nested "include" and "tpl" calls, avoiding infinite recursions.
+const maxIncludeDepth = 100
+
+// errUnboundTemplate is returned by FuncMap functions needing to be bound to the executed template set or destination directory.
+var errUnboundTemplate = errors.New("function is only available in templates executed by ApplyTemplate or ApplyPatches")
+
+// destFuncs returns the read-only filesystem functions scoped to dir (relative to root),
+// each access being emitted as an EventFileRead.
+func destFuncs(ctx context.Context, root *os.Root, dir string) template.FuncMap {
+	fsys := root.FS()
+	if dir != "" {
+		fsys, _ = fs.Sub(fsys, filepath.ToSlash(dir)) // dir is already validated as local
+	}
+
+	// access validates input name and emits its access by function
+	access := func(function, name string) (string, error) {
+		clean := path.Clean(name)
+		if !fs.ValidPath(clean) {
+			return "", &EscapeError{Path: name}
+		}
+		emit(ctx, Event{Kind: EventFileRead, Path: filepath.Join(root.Name(), dir, filepath.FromSlash(clean)), Reason: function})
+		return clean, nil
+	}
+
+	// confine wraps input error on name into an EscapeError when it escapes the root (see confined)
+	confine := func(name, clean string, err error) error {
+		if err != nil && escapes(root, filepath.Join(dir, filepath.FromSlash(clean)), true) {
+			return &EscapeError{Path: name, Err: err}
+		}
+		return err
+	}
+
+	return template.FuncMap{
+		"fileExists": func(name string) (bool, error) {
+			clean, err := access("fileExists", name)
+			if err != nil {
+				return false, err
+			}
+			if _, err := fs.Stat(fsys, clean); err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return false, nil
+				}
+				return false, confine(name, clean, err)
+			}
+			return true, nil
+		},
+		"glob": func(pattern string) ([]string, error) {
+			clean, err := access("glob", pattern)
+			if err != nil {
+				return nil, err
+			}
+			matches, err := fs.Glob(fsys, clean)
+			if err != nil {
+				return nil, err // only ErrBadPattern, file system errors are ignored
+			}
+			return matches, nil
+		},
+		"listDir": func(name string) ([]string, error) {
+			clean, err := access("listDir", name)
+			if err != nil {
+				return nil, err
+			}
+			entries, err := fs.ReadDir(fsys, clean)
+			if err != nil {
+				return nil, confine(name, clean, err)
+			}
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			return names, nil
+		},
+		"readFile": func(name string) (string, error) {
+			clean, err := access("readFile", name)
+			if err != nil {
+				return "", err
+			}
+			content, err := fs.ReadFile(fsys, clean)
+			if err != nil {
+				return "", confine(name, clean, err)
+			}
+			return string(content), nil
+		},
+	}
+}
+
+// includeFuncs binds "include" and "tpl" functions to input template set.
+type includeFuncs struct {
+	depth    int
+	exceeded error
+
+	// funcs are the functions specific to the executed template (see Template.FuncMap),
+	// applied after "include" and "tpl" since they take precedence over default ones.
+	funcs template.FuncMap
+}
+
+// bind overrides "include" and "tpl" functions of tt with the ones executing tt templates.
+func (i *includeFuncs) bind(tt *template.Template) *template.Template {
+	tt.Funcs(template.FuncMap{
+		"include": func(name string, data any) (string, error) {
+			if err := i.enter(); err != nil {
+				return "", err
+			}
+			defer i.leave()
+
+			var buf strings.Builder
+			if err := tt.ExecuteTemplate(&buf, name, data); err != nil {
+				return "", i.fail(fmt.Sprintf("include '%s'", name), err)
+			}
+			return buf.String(), nil
+		},
+		"tpl": func(text string, data any) (string, error) {
+			if err := i.enter(); err != nil {
+				return "", err
+			}
+			defer i.leave()
+
+			// parse text in a clone to avoid altering tt while keeping access to its defined templates
+			clone, err := tt.Clone()
+			if err != nil {
+				return "", fmt.Errorf("tpl clone: %w", err)
+			}
+			tpl, err := i.bind(clone).New("tpl").Parse(text)
+			if err != nil {
+				return "", fmt.Errorf("tpl parse: %w", err)
+			}
+
+			var buf strings.Builder
+			if err := tpl.Execute(&buf, data); err != nil {
+				return "", i.fail("tpl execute", err)
+			}
+			return buf.String(), nil
+		},
+	})
+	return tt.Funcs(i.funcs)
+}
+
+// enter increments the nested calls depth, returning an error when it's exceeded.
+func (i *includeFuncs) enter() error {
+	if i.depth >= maxIncludeDepth {
+		i.exceeded = fmt.Errorf("maximum nested calls depth (%d) exceeded", maxIncludeDepth)
+		return i.exceeded
 	}
+	i.depth++
+	return nil
+}
+
+// leave decrements the nested calls depth.
+func (i *includeFuncs) leave() {
+	i.depth--
+}
+
+// fail returns input execution error of the current call prefixed with input call description.
+//
+// When the nested calls depth was exceeded, the depth error is returned as is by nested calls
+// and prefixed only once by the outermost call, instead of one prefix (and text/template location) per nested call.
+func (i *includeFuncs) fail(call string, err error) error {
+	if i.exceeded != nil {
+		if i.depth > 1 {
+			return i.exceeded
+		}
+		err, i.exceeded = i.exceeded, nil
+	}
+	return fmt.Errorf("%s: %w", call, err)
 }
 
 // cutAfter cuts the input string at the first separator appearance
@@ -51,13 +260,54 @@ func mergeMaps(dst map[string]any, src ...any) (map[string]any, error) {
 	return dst, errors.Join(errs...)
 }
 
+// semverTags returns input tags starting with prefix (e.g. "v" or "module/v") and being semantic versions,
+// sorted in ascending order and without prereleases.
+//
+// Arguments are ordered for pipelines, e.g. {{ .VCS.Tags | semverTags "v" }}.
+func semverTags(prefix string, tags []string) []string {
+	parsed := versions.Parse(tags, prefix, false)
+	out := make([]string, 0, len(parsed))
+	for _, version := range parsed {
+		out = append(out, prefix+version.Original())
+	}
+	return out
+}
+
+// semverLatest returns the latest tag (without prereleases) of input tags starting with prefix
+// and being semantic versions, or an empty string when there's none.
+//
+// Arguments are ordered for pipelines, e.g. {{ .VCS.Tags | semverLatest "module/v" }}.
+func semverLatest(prefix string, tags []string) string {
+	tagged := semverTags(prefix, tags)
+	if len(tagged) == 0 {
+		return ""
+	}
+	return tagged[len(tagged)-1]
+}
+
+// semverBump bumps the part ("major", "minor" or "patch") of input tag, keeping its prefix,
+// e.g. "module/v1.2.3" gives "module/v1.3.0" when bumping "minor".
+//
+// Arguments are ordered for pipelines, e.g. {{ .VCS.Tags | semverLatest "v" | default "v0.0.0" | semverBump "minor" }}.
+func semverBump(part, tag string) (string, error) {
+	prefix, version, ok := versions.Split(tag)
+	if !ok {
+		return "", fmt.Errorf("invalid semantic version tag '%s'", tag)
+	}
+	next, err := versions.Bump(version, part)
+	if err != nil {
+		return "", err
+	}
+	return prefix + next.String(), nil
+}
+
 // toQuery transforms a specific into its query parameter format.
 func toQuery(in string) string {
 	return url.QueryEscape(in)
 }
 
 // toYAML takes an interface, marshals it to yaml, and returns a string.
-// It will always return a string, even on marshal error (empty string).
+// It will always return a string, even on marshal error (empty string), see mustToYaml to fail instead.
 //
 // This is designed to be called from a go template.
 func toYAML(v any) string {
@@ -69,11 +319,318 @@ func toYAML(v any) string {
 	return string(bytes.TrimSuffix(b, []byte("\n")))
 }
 
+// serializeOptions represents the formatting options of serialization functions (mustToYaml, toToml and toPrettyJson).
+type serializeOptions struct {
+	flow     bool
+	indent   int
+	sortKeys bool
+}
+
+// serializer represents a serialization function (mustToYaml, toToml or toPrettyJson) with its supported options.
+type serializer struct {
+	// function is the template function name, used in errors.
+	function string
+
+	// indent is the default indentation and minIndent the minimal one.
+	indent, minIndent int
+
+	// options is the slice of supported options.
+	options []string
+
+	// marshal and unmarshal are the format functions, used to sort keys with the format own struct tags.
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(content []byte, out any) error
+}
+
+// args returns the value to serialize (last argument, for pipelines) and the formatting options
+// given before it (e.g. "indent=4", "sortKeys" or "flow"), options being validated against the serializer ones.
+func (s serializer) args(args []any) (any, serializeOptions, error) {
+	so := serializeOptions{indent: s.indent}
+	if len(args) == 0 {
+		return nil, so, fmt.Errorf("%s: missing value to serialize", s.function)
+	}
+
+	for _, arg := range args[:len(args)-1] {
+		option, ok := arg.(string)
+		if !ok {
+			return nil, so, fmt.Errorf("%s: invalid option '%v', must be a string", s.function, arg)
+		}
+		name, value, _ := strings.Cut(option, "=")
+		if !slices.Contains(s.options, name) {
+			return nil, so, fmt.Errorf("%s: unsupported option '%s', must be one of %v", s.function, name, s.options)
+		}
+
+		switch name {
+		case "flow":
+			so.flow = value == "" || value == "true"
+		case "indent":
+			i, err := strconv.Atoi(value)
+			if err != nil || i < s.minIndent {
+				return nil, so, fmt.Errorf("%s: invalid indent '%s', must be an integer greater or equal to %d", s.function, value, s.minIndent)
+			}
+			so.indent = i
+		case "sortKeys":
+			so.sortKeys = value == "" || value == "true"
+		}
+	}
+
+	v := args[len(args)-1]
+	if so.sortKeys {
+		// structs fields are serialized in their declaration order, maps keys are always sorted
+		sorted, err := s.sortedKeys(v)
+		if err != nil {
+			return nil, so, fmt.Errorf("%s: sort keys: %w", s.function, err)
+		}
+		v = sorted
+	}
+	return v, so, nil
+}
+
+// sortedKeys converts input value into its generic representation (maps, slices and scalars)
+// with the serializer format, for its keys to be serialized in alphabetical order with the format own struct tags.
+func (s serializer) sortedKeys(v any) (any, error) {
+	content, err := s.marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var sorted any
+	if err := s.unmarshal(content, &sorted); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+var (
+	yamlSerializer = serializer{
+		function:  "mustToYaml",
+		indent:    2,
+		minIndent: 1, // without indentation, nested mappings would be serialized as siblings of their parent
+		options:   []string{"flow", "indent", "sortKeys"},
+		marshal:   yaml.Marshal,
+		unmarshal: yaml.Unmarshal,
+	}
+
+	tomlSerializer = serializer{
+		function:  "toToml",
+		options:   []string{"indent", "sortKeys"},
+		marshal:   toml.Marshal,
+		unmarshal: toml.Unmarshal,
+	}
+
+	jsonSerializer = serializer{
+		function: "toPrettyJson",
+		indent:   2,
+		options:  []string{"indent", "sortKeys"},
+		marshal:  json.Marshal,
+		unmarshal: func(content []byte, out any) error {
+			decoder := json.NewDecoder(bytes.NewReader(content))
+			decoder.UseNumber() // numbers must be serialized back as is (e.g. integers not as floats)
+			return decoder.Decode(out)
+		},
+	}
+)
+
+// mustToYAML marshals the last argument to YAML, failing template execution on error,
+// with options "indent=N" (default 2, at least 1), "sortKeys" and "flow" (YAML flow style) given before it.
+//
+// This is designed to be called from a go template, e.g. {{ .Values | mustToYaml "indent=4" "sortKeys" }}.
+func mustToYAML(args ...any) (string, error) {
+	v, so, err := yamlSerializer.args(args)
+	if err != nil {
+		return "", err
+	}
+	b, err := yaml.MarshalWithOptions(v, yaml.Indent(so.indent), yaml.Flow(so.flow))
+	if err != nil {
+		return "", fmt.Errorf("mustToYaml: %w", err)
+	}
+	return string(bytes.TrimSuffix(b, []byte("\n"))), nil
+}
+
+// toTOML marshals the last argument to TOML, failing template execution on error,
+// with options "indent=N" (indents nested tables, default 0) and "sortKeys" given before it.
+//
+// This is designed to be called from a go template, e.g. {{ .Values | toToml "indent=2" }}.
+func toTOML(args ...any) (string, error) {
+	v, so, err := tomlSerializer.args(args)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	if so.indent > 0 {
+		encoder.SetIndentTables(true)
+		encoder.SetIndentSymbol(strings.Repeat(" ", so.indent))
+	}
+	if err := encoder.Encode(v); err != nil {
+		return "", fmt.Errorf("toToml: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// toPrettyJSON marshals the last argument to indented JSON, failing template execution on error,
+// with options "indent=N" (default 2) and "sortKeys" given before it.
+//
+// It overrides sprig "toPrettyJson" which swallows errors, keeping its output as is without options
+// (two spaces indentation and HTML characters escaped, e.g. "&" gives "\u0026").
+// This is designed to be called from a go template, e.g. {{ .Values | toPrettyJson "indent=4" }}.
+func toPrettyJSON(args ...any) (string, error) {
+	v, so, err := jsonSerializer.args(args)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", strings.Repeat(" ", so.indent))
+	if err := encoder.Encode(v); err != nil {
+		return "", fmt.Errorf("toPrettyJson: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// fromYAML unmarshals input YAML string, failing template execution on error.
+func fromYAML(in string) (any, error) {
+	var out any
+	if err := yaml.Unmarshal([]byte(in), &out); err != nil {
+		return nil, fmt.Errorf("fromYaml: %w", err)
+	}
+	return out, nil
+}
+
+// fromTOML unmarshals input TOML string, failing template execution on error.
+func fromTOML(in string) (map[string]any, error) {
+	var out map[string]any
+	if err := toml.Unmarshal([]byte(in), &out); err != nil {
+		return nil, fmt.Errorf("fromToml: %w", err)
+	}
+	return out, nil
+}
+
 var slugRegexp = regexp.MustCompile("[^a-zA-Z0-9]+")
 
 // ToSlug transforms an input string into its slug representation,
 // keeping only letters and numbers and replacing everything else by the dash '-'.
+//
+// Diacritics and common ligatures are transliterated beforehand, e.g. "Café Société" gives "cafe-societe".
 func ToSlug(in string) string {
-	spaced := strings.TrimSpace(slugRegexp.ReplaceAllString(in, " "))
+	spaced := strings.TrimSpace(slugRegexp.ReplaceAllString(transliterate(in), " "))
 	return strings.ToLower(strings.ReplaceAll(spaced, " ", "-"))
 }
+
+// ErrInvalidName is returned by ToGoPackage, ToEnvVar, ToDNSLabel and ToNpmPackage
+// when no valid name can be derived from their input (e.g. only numbers or non latin characters).
+var ErrInvalidName = errors.New("no valid name")
+
+// ToGoPackage transforms an input string into a valid Go package name,
+// keeping only lowercase letters and numbers (e.g. "My-Tool v2" gives "mytoolv2").
+//
+// Leading numbers are removed and a "pkg" suffix is added to Go keywords (e.g. "type" gives "typepkg").
+// It returns ErrInvalidName when nothing remains (e.g. "123").
+func ToGoPackage(in string) (string, error) {
+	name := strings.TrimLeft(strings.ReplaceAll(ToSlug(in), "-", ""), "0123456789")
+	if name == "" {
+		return "", fmt.Errorf("go package from '%s': %w", in, ErrInvalidName)
+	}
+	if token.IsKeyword(name) {
+		return name + "pkg", nil
+	}
+	return name, nil
+}
+
+// ToEnvVar transforms an input string into a valid environment variable name,
+// keeping only uppercase letters, numbers and underscores (e.g. "api.base-url" gives "API_BASE_URL").
+//
+// An underscore is prepended when the name starts with a number.
+// It returns ErrInvalidName when nothing remains (e.g. "日本").
+func ToEnvVar(in string) (string, error) {
+	name := strings.ToUpper(strings.ReplaceAll(ToSlug(in), "-", "_"))
+	if name == "" {
+		return "", fmt.Errorf("environment variable from '%s': %w", in, ErrInvalidName)
+	}
+	if unicode.IsDigit(rune(name[0])) {
+		return "_" + name, nil
+	}
+	return name, nil
+}
+
+// dnsLabelMaxLength is the maximum length of a DNS-1123 label.
+const dnsLabelMaxLength = 63
+
+// ToDNSLabel transforms an input string into a valid Kubernetes DNS-1123 label
+// (lowercase letters, numbers and dashes, starting and ending with a letter or a number, at most 63 characters).
+//
+// It returns ErrInvalidName when nothing remains (e.g. "日本").
+func ToDNSLabel(in string) (string, error) {
+	label := ToSlug(in)
+	if len(label) > dnsLabelMaxLength {
+		label = strings.TrimRight(label[:dnsLabelMaxLength], "-")
+	}
+	if label == "" {
+		return "", fmt.Errorf("dns label from '%s': %w", in, ErrInvalidName)
+	}
+	return label, nil
+}
+
+// npmPackageMaxLength is the maximum length of a npm package name (including its scope).
+const npmPackageMaxLength = 214
+
+var npmRegexp = regexp.MustCompile("[^a-z0-9._]+")
+
+// ToNpmPackage transforms an input string into a valid npm package name
+// (lowercase letters, numbers, dashes, dots and underscores, not starting with a dot or an underscore, at most 214 characters).
+//
+// Scoped names are kept scoped, e.g. "@My Org/My Package" gives "@my-org/my-package".
+// It returns ErrInvalidName when nothing remains of the name or of its scope (e.g. "@日本/x").
+func ToNpmPackage(in string) (string, error) {
+	normalize := func(part string) (string, error) {
+		part = npmRegexp.ReplaceAllString(strings.ToLower(transliterate(part)), "-")
+		part = strings.TrimRight(strings.TrimLeft(part, "-._"), "-")
+		if part == "" {
+			return "", fmt.Errorf("npm package from '%s': %w", in, ErrInvalidName)
+		}
+		return part, nil
+	}
+
+	var name string
+	if scope, pkg, ok := strings.Cut(strings.TrimSpace(in), "/"); ok && strings.HasPrefix(scope, "@") {
+		scope, err := normalize(scope[1:])
+		if err != nil {
+			return "", err
+		}
+		if pkg, err = normalize(pkg); err != nil {
+			return "", err
+		}
+		name = "@" + scope + "/" + pkg
+	} else {
+		var err error
+		if name, err = normalize(in); err != nil {
+			return "", err
+		}
+	}
+	if len(name) > npmPackageMaxLength {
+		name = strings.TrimRight(name[:npmPackageMaxLength], "-._")
+	}
+	return name, nil
+}
+
+// ligatures is the replacer for letters not decomposable into an ASCII letter and its diacritics.
+//
+// German umlauts are transliterated with a trailing "e" (e.g. "Müller" gives "Mueller") as German speakers do
+// when diacritics aren't available, other languages using them (e.g. Turkish or Finnish) being less common in names.
+var ligatures = strings.NewReplacer(
+	"Æ", "AE", "æ", "ae", "Œ", "OE", "œ", "oe", "ß", "ss", "ẞ", "SS",
+	"Ä", "AE", "ä", "ae", "Ö", "OE", "ö", "oe", "Ü", "UE", "ü", "ue",
+	"Ø", "O", "ø", "o", "Đ", "D", "đ", "d", "Ł", "L", "ł", "l", "Þ", "TH", "þ", "th",
+)
+
+// transliterate removes diacritics (e.g. "é" gives "e") and replaces common ligatures (e.g. "œ" gives "oe")
+// of input string, compatibility characters being decomposed too (e.g. "ﬁ" gives "fi").
+func transliterate(in string) string {
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, ligatures.Replace(norm.NFC.String(in))) // composed for ligatures to match
+	if err != nil {
+		return in
+	}
+	return out
+}
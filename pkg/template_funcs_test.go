@@ -1,8 +1,10 @@
 package engine_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/Masterminds/sprig/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -54,6 +56,64 @@ func TestMergeMaps(t *testing.T) {
 	})
 }
 
+func TestIncludeUnbound(t *testing.T) {
+	for _, name := range []string{"include", "tpl"} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			fn, ok := engine.FuncMap()[name].(func(string, any) (string, error))
+			require.True(t, ok)
+
+			// Act
+			_, err := fn("name", nil)
+
+			// Assert
+			assert.ErrorContains(t, err, "function is only available in templates executed by ApplyTemplate or ApplyPatches")
+		})
+	}
+}
+
+func TestSemverFuncs(t *testing.T) {
+	tags := []string{"v1.10.0", "v1.2.0", "v2.0.0-rc.1", "api/v0.3.0", "api/v0.1.0", "latest"}
+
+	t.Run("tags", func(t *testing.T) {
+		// Arrange
+		semverTags, ok := engine.FuncMap()["semverTags"].(func(string, []string) []string)
+		require.True(t, ok)
+
+		// Act & Assert
+		assert.Equal(t, []string{"v1.2.0", "v1.10.0"}, semverTags("v", tags))
+		assert.Equal(t, []string{"api/v0.1.0", "api/v0.3.0"}, semverTags("api/v", tags))
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		// Arrange
+		semverLatest, ok := engine.FuncMap()["semverLatest"].(func(string, []string) string)
+		require.True(t, ok)
+
+		// Act & Assert
+		assert.Equal(t, "v1.10.0", semverLatest("v", tags))
+		assert.Equal(t, "api/v0.3.0", semverLatest("api/v", tags))
+		assert.Empty(t, semverLatest("web/v", tags))
+	})
+
+	t.Run("bump", func(t *testing.T) {
+		// Arrange
+		semverBump, ok := engine.FuncMap()["semverBump"].(func(string, string) (string, error))
+		require.True(t, ok)
+
+		// Act
+		next, err := semverBump("minor", "api/v0.3.0")
+		_, invalidErr := semverBump("minor", "latest")
+		_, partErr := semverBump("build", "v1.0.0")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "api/v0.4.0", next)
+		assert.ErrorContains(t, invalidErr, "invalid semantic version tag 'latest'")
+		assert.ErrorContains(t, partErr, "unknown version part 'build'")
+	})
+}
+
 func TestToQuery(t *testing.T) {
 	fm := engine.FuncMap()["toQuery"]
 	toQuery, ok := fm.(func(in string) string)
@@ -82,6 +142,198 @@ func TestToYAML(t *testing.T) {
 	})
 }
 
+func TestSerializeFuncs(t *testing.T) {
+	type value struct {
+		Name  string         `json:"name"  toml:"name"  yaml:"name"`
+		Tags  []string       `json:"tags"  toml:"tags"  yaml:"tags"`
+		Attrs map[string]int `json:"attrs" toml:"attrs" yaml:"attrs"`
+	}
+	v := value{Name: "<name>", Tags: []string{"a", "b"}, Attrs: map[string]int{"z": 1, "a": 2}}
+
+	type taggedValue struct {
+		Zed    string `json:"zedName" toml:"zed_key"    yaml:"zed_name"`
+		Hidden string `json:"-"       toml:"hidden_key" yaml:"hidden"`
+	}
+	tagged := taggedValue{Zed: "z", Hidden: "h"}
+
+	cases := []struct {
+		name     string
+		function string
+		args     []any
+		expected string
+	}{
+		{
+			name:     "yaml",
+			function: "mustToYaml",
+			args:     []any{v},
+			expected: "name: <name>\ntags:\n- a\n- b\nattrs:\n  a: 2\n  z: 1",
+		},
+		{
+			name:     "yaml_options",
+			function: "mustToYaml",
+			args:     []any{"indent=4", "sortKeys", v},
+			expected: "attrs:\n    a: 2\n    z: 1\nname: <name>\ntags:\n- a\n- b",
+		},
+		{
+			name:     "yaml_flow",
+			function: "mustToYaml",
+			args:     []any{"flow", v},
+			expected: "{name: <name>, tags: [a, b], attrs: {a: 2, z: 1}}",
+		},
+		{
+			name:     "toml",
+			function: "toToml",
+			args:     []any{v},
+			expected: "name = '<name>'\ntags = ['a', 'b']\n\n[attrs]\na = 2\nz = 1",
+		},
+		{
+			name:     "toml_options",
+			function: "toToml",
+			args:     []any{"indent=2", "sortKeys", v},
+			expected: "name = '<name>'\ntags = ['a', 'b']\n\n[attrs]\n  a = 2\n  z = 1",
+		},
+		{
+			name:     "json",
+			function: "toPrettyJson",
+			args:     []any{v},
+			expected: "{\n  \"name\": \"\\u003cname\\u003e\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ],\n  \"attrs\": {\n    \"a\": 2,\n    \"z\": 1\n  }\n}",
+		},
+		{
+			name:     "json_options",
+			function: "toPrettyJson",
+			args:     []any{"indent=1", "sortKeys=true", map[string]any{"b": 1, "a": []int{1}}},
+			expected: "{\n \"a\": [\n  1\n ],\n \"b\": 1\n}",
+		},
+		{
+			name:     "yaml_sort_format_tags",
+			function: "mustToYaml",
+			args:     []any{"sortKeys", tagged},
+			expected: "hidden: h\nzed_name: z",
+		},
+		{
+			name:     "toml_sort_format_tags",
+			function: "toToml",
+			args:     []any{"sortKeys", tagged},
+			expected: "hidden_key = 'h'\nzed_key = 'z'",
+		},
+		{
+			name:     "json_sort_format_tags",
+			function: "toPrettyJson",
+			args:     []any{"sortKeys", tagged},
+			expected: "{\n  \"zedName\": \"z\"\n}",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			serialize, ok := engine.FuncMap()[tc.function].(func(...any) (string, error))
+			require.True(t, ok)
+
+			// Act
+			result, err := serialize(tc.args...)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			function string
+			args     []any
+			expected string
+		}{
+			{function: "mustToYaml", args: []any{make(chan int)}, expected: "mustToYaml: unknown value type chan int"},
+			{function: "mustToYaml", expected: "mustToYaml: missing value to serialize"},
+			{function: "mustToYaml", args: []any{"indent=-1", v}, expected: "mustToYaml: invalid indent '-1'"},
+			{function: "mustToYaml", args: []any{"indent=0", v}, expected: "mustToYaml: invalid indent '0'"},
+			{function: "toToml", args: []any{"indent=-1", v}, expected: "toToml: invalid indent '-1'"},
+			{function: "mustToYaml", args: []any{4, v}, expected: "mustToYaml: invalid option '4'"},
+			{function: "toToml", args: []any{[]int{1}}, expected: "toToml: toml: cannot encode a []int as a document root"},
+			{function: "toToml", args: []any{"flow", v}, expected: "toToml: unsupported option 'flow'"},
+			{function: "toPrettyJson", args: []any{make(chan int)}, expected: "toPrettyJson: json: unsupported type: chan int"},
+			{function: "toPrettyJson", args: []any{"sortKeys", make(chan int)}, expected: "toPrettyJson: sort keys"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.expected, func(t *testing.T) {
+				// Arrange
+				serialize, ok := engine.FuncMap()[tc.function].(func(...any) (string, error))
+				require.True(t, ok)
+
+				// Act
+				_, err := serialize(tc.args...)
+
+				// Assert
+				assert.ErrorContains(t, err, tc.expected)
+			})
+		}
+	})
+}
+
+func TestToPrettyJSONSprig(t *testing.T) {
+	// Arrange
+	toPrettyJSON, ok := engine.FuncMap()["toPrettyJson"].(func(...any) (string, error))
+	require.True(t, ok)
+	sprigToPrettyJSON, ok := sprig.FuncMap()["toPrettyJson"].(func(any) string)
+	require.True(t, ok)
+	v := map[string]any{"url": "https://example.com?a=1&b=<2>", "nested": map[string]any{"list": []int{1, 2}}}
+
+	// Act
+	result, err := toPrettyJSON(v)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, sprigToPrettyJSON(v), result)
+	assert.Contains(t, result, `\u0026`)
+}
+
+func TestDeserializeFuncs(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		// Arrange
+		fromYaml, ok := engine.FuncMap()["fromYaml"].(func(string) (any, error))
+		require.True(t, ok)
+
+		// Act
+		result, err := fromYaml("name: value\ntags: [a]")
+		_, invalidErr := fromYaml("name: [")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "value", "tags": []any{"a"}}, result)
+		assert.ErrorContains(t, invalidErr, "fromYaml")
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		// Arrange
+		fromToml, ok := engine.FuncMap()["fromToml"].(func(string) (map[string]any, error))
+		require.True(t, ok)
+
+		// Act
+		result, err := fromToml("name = 'value'\n[table]\nkey = 1")
+		_, invalidErr := fromToml("name = ")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "value", "table": map[string]any{"key": int64(1)}}, result)
+		assert.ErrorContains(t, invalidErr, "fromToml")
+	})
+}
+
+func TestNoticeFunc(t *testing.T) {
+	fm := engine.FuncMap()["notice"]
+	notice, ok := fm.(func(generator string) string)
+	require.True(t, ok)
+
+	t.Run("success", func(t *testing.T) {
+		// Act
+		result := notice("kickr")
+
+		// Assert
+		assert.Equal(t, "Code generated by kickr; DO NOT EDIT.", result)
+	})
+}
+
 func TestCutAfter(t *testing.T) {
 	fm := engine.FuncMap()["cutAfter"]
 	cut, ok := fm.(func(in, sep string) string)
@@ -108,4 +360,141 @@ func TestSlug(t *testing.T) {
 		// Assert
 		assert.Equal(t, "something-things-others-and-not-none", result)
 	})
+
+	t.Run("success_unicode", func(t *testing.T) {
+		cases := map[string]string{
+			"Café Société":       "cafe-societe",
+			"Größe & Übermaß":    "groesse-uebermass",
+			"Mu\u0308ller":       "mueller", // decomposed umlaut
+			"Œuvre d'Ægir":       "oeuvre-d-aegir",
+			"ﬁchier Łódź Ørsted": "fichier-lodz-orsted",
+			"日本語 project":        "project",
+		}
+		for in, expected := range cases {
+			t.Run(in, func(t *testing.T) {
+				// Act
+				result := toSlug(in)
+
+				// Assert
+				assert.Equal(t, expected, result)
+			})
+		}
+	})
+}
+
+func TestToGoPackage(t *testing.T) {
+	cases := map[string]string{
+		"My-Tool v2":  "mytoolv2",
+		"Société_API": "societeapi",
+		"2fa service": "faservice",
+		"type":        "typepkg",
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			// Act
+			result, err := engine.ToGoPackage(in)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	for _, in := range []string{"", "123", "日本"} {
+		t.Run("error_"+in, func(t *testing.T) {
+			// Act
+			_, err := engine.ToGoPackage(in)
+
+			// Assert
+			assert.ErrorIs(t, err, engine.ErrInvalidName)
+		})
+	}
+}
+
+func TestToEnvVar(t *testing.T) {
+	cases := map[string]string{
+		"api.base-url": "API_BASE_URL",
+		"Clé secrète":  "CLE_SECRETE",
+		"2fa.enabled":  "_2FA_ENABLED",
+		"Größe":        "GROESSE",
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			// Act
+			result, err := engine.ToEnvVar(in)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	for _, in := range []string{"", "日本"} {
+		t.Run("error_"+in, func(t *testing.T) {
+			// Act
+			_, err := engine.ToEnvVar(in)
+
+			// Assert
+			assert.ErrorIs(t, err, engine.ErrInvalidName)
+		})
+	}
+}
+
+func TestToDNSLabel(t *testing.T) {
+	cases := map[string]string{
+		"My_Service.Prod":              "my-service-prod",
+		"-Déploiement-":                "deploiement",
+		strings.Repeat("a", 62) + "-b": strings.Repeat("a", 62),
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			// Act
+			result, err := engine.ToDNSLabel(in)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+			assert.LessOrEqual(t, len(result), 63)
+		})
+	}
+
+	for _, in := range []string{"", "日本", "---"} {
+		t.Run("error_"+in, func(t *testing.T) {
+			// Act
+			_, err := engine.ToDNSLabel(in)
+
+			// Assert
+			assert.ErrorIs(t, err, engine.ErrInvalidName)
+		})
+	}
+}
+
+func TestToNpmPackage(t *testing.T) {
+	cases := map[string]string{
+		"My Package":             "my-package",
+		"@My Org/Mön Paquet":     "@my-org/moen-paquet",
+		"_private.lib":           "private.lib",
+		".hidden~tool":           "hidden-tool",
+		strings.Repeat("a", 220): strings.Repeat("a", 214),
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			// Act
+			result, err := engine.ToNpmPackage(in)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	for _, in := range []string{"", "日本", "@日本/x", "@org/~"} {
+		t.Run("error_"+in, func(t *testing.T) {
+			// Act
+			_, err := engine.ToNpmPackage(in)
+
+			// Assert
+			assert.ErrorIs(t, err, engine.ErrInvalidName)
+		})
+	}
 }
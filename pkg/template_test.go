@@ -100,6 +100,129 @@ func TestApplyTemplate(t *testing.T) {
 		assert.Equal(t, buf.String(), fmt.Sprintf("not generating '%s' since it already exists (or was modified manually)", template.Out))
 	})
 
+	t.Run("success_owned_by_other_generator", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Globs:  []string{"file.txt" + engine.TmplExtension},
+			Notice: "kickr",
+			Out:    "file.txt",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte("content"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("Code generated by other; DO NOT EDIT."), files.RwRR))
+
+		buf := strings.Builder{}
+		logger := engine.NewTestLogger(&buf)
+		configure(t, logger)
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, buf.String(), fmt.Sprintf("not generating '%s' since it's owned by generator 'other'", template.Out))
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "Code generated by other; DO NOT EDIT.", string(content))
+	})
+
+	t.Run("success_policy_func", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			GeneratePolicyFunc: func(c testconfig) engine.GeneratePolicy {
+				if c.Str == "always" {
+					return engine.PolicyAlways
+				}
+				return engine.PolicyNever
+			},
+			Globs: []string{"file.txt" + engine.TmplExtension},
+			Out:   "file.txt",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte("{{ .Str }}"), files.RwRR))
+
+		// Act
+		never := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "never"})
+		require.NoFileExists(t, filepath.Join(destdir, template.Out))
+		always := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "always"})
+
+		// Assert
+		require.NoError(t, never)
+		require.NoError(t, always)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "always", string(content))
+	})
+
+	t.Run("success_checksums", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			GeneratePolicy: engine.PolicyHash,
+			Globs:          []string{"file.txt" + engine.TmplExtension},
+			Out:            "file.txt",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte("{{ .Str }}"), files.RwRR))
+
+		checksums, err := engine.ReadChecksums(filepath.Join(destdir, "checksums.json"))
+		require.NoError(t, err)
+		engine.Configure(engine.WithChecksums(checksums))
+		t.Cleanup(func() { engine.Configure() })
+
+		// Act
+		require.NoError(t, engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "first"}))
+		require.NoError(t, engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "second"}))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("manual"), files.RwRR))
+		err = engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "third"})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "manual", string(content))
+	})
+
+	t.Run("success_checksums_patched", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			GeneratePolicy: engine.PolicyHash,
+			Globs:          []string{"file.txt" + engine.TmplExtension},
+			Out:            "file.txt",
+			Patches:        []string{"file.patch"},
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte("{{ .Str }}\n"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Patches[0]), []byte(`
+diff --git a/file.txt b/file.txt
+index 332d5ce..39af8aa 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,1 +1,2 @@
+ {{ .Str }}
++patched`), files.RwRR))
+
+		checksums, err := engine.ReadChecksums(filepath.Join(destdir, "checksums.json"))
+		require.NoError(t, err)
+		engine.Configure(engine.WithChecksums(checksums))
+		t.Cleanup(func() { engine.Configure() })
+
+		// Act
+		require.NoError(t, engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "value"}))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("value\nuser edit\n"), files.RwRR))
+		require.NoError(t, engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "value"}))
+		err = engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "value"})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "user edit")
+	})
+
 	t.Run("error_remove", func(t *testing.T) {
 		if os.Geteuid() == 0 {
 			t.Skip("running as root bypasses permission checks")
@@ -145,6 +268,198 @@ func TestApplyTemplate(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "pong", string(content))
 	})
+
+	t.Run("success_template_funcs_data", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Data: func(config testconfig) any { return map[string]string{"Name": config.Str} },
+			FuncMap: func(config testconfig) template.FuncMap {
+				return template.FuncMap{"ping": func() string { return config.Str }}
+			},
+			Globs:   []string{"file.txt" + engine.TmplExtension},
+			Out:     "file.txt",
+			Patches: []string{"file.patch"},
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte("{{ .Name }} {{ ping }}\n"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Patches[0]), []byte(`
+diff --git a/file.txt b/file.txt
+index 332d5ce..39af8aa 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,1 +1,2 @@
+ {{ .Name }} {{ ping }}
++patched`), files.RwRR))
+
+		buf := strings.Builder{}
+		logger := engine.NewTestLogger(&buf)
+		configure(t, logger)
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "engine"})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "engine engine\npatched", string(content))
+	})
+
+	t.Run("success_template_funcs_precedence", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			FuncMap: func(testconfig) template.FuncMap {
+				return template.FuncMap{
+					"include":  func(name string, _ any) string { return "include " + name },
+					"readFile": func(name string) string { return "read " + name },
+				}
+			},
+			Globs:   []string{"file.txt" + engine.TmplExtension},
+			Out:     "file.txt",
+			Patches: []string{"file.patch"},
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]),
+			[]byte(`{{ include "x" . }}, {{ readFile "y" }}, {{ tpl "{{ include \"z\" . }}" . }}`+"\n"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Patches[0]), []byte(`
+diff --git a/file.txt b/file.txt
+index 332d5ce..39af8aa 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,1 +1,2 @@
+ include x, read y, include z
++{{ include "x" . }}, {{ readFile "y" }}`), files.RwRR))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "include x, read y, include z\ninclude x, read y", string(content))
+	})
+
+	t.Run("error_include_depth", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Globs: []string{"file.txt" + engine.TmplExtension},
+			Out:   "file.txt",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]),
+			[]byte(`{{ define "loop" }}{{ include "loop" . }}{{ end }}{{ include "loop" . }}`), files.RwRR))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{})
+
+		// Assert
+		require.ErrorContains(t, err, "include 'loop': maximum nested calls depth (100) exceeded")
+		assert.Equal(t, 1, strings.Count(err.Error(), "include 'loop'")) // not repeated for each nested call
+		assert.Less(t, len(err.Error()), 300)
+	})
+
+	t.Run("success_include_tpl", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Globs: []string{"file.yml" + engine.TmplExtension, "_helpers" + engine.TmplExtension},
+			Out:   "file.yml",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]),
+			[]byte(`labels:{{ include "labels" . | nindent 2 }}
+name: {{ tpl "{{ .Str }}-{{ include \"suffix\" . }}" . }}`), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[1]),
+			[]byte(`{{ define "labels" }}app: {{ .Str }}
+tier: backend{{ end }}{{ define "suffix" }}app{{ end }}`), files.RwRR))
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{Str: "engine"})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "labels:\n  app: engine\n  tier: backend\nname: engine-app", string(content))
+	})
+
+	t.Run("error_fs_escape", func(t *testing.T) {
+		cases := map[string]string{
+			"parent":   `{{ readFile "../secret" }}`,
+			"absolute": `{{ fileExists "/etc/passwd" }}`,
+			"symlink":  `{{ readFile "link" }}`,
+		}
+		for name, content := range cases {
+			t.Run(name, func(t *testing.T) {
+				// Arrange
+				srcdir := t.TempDir()
+				destdir := t.TempDir()
+				template := engine.Template[testconfig]{
+					Globs: []string{"file.txt" + engine.TmplExtension},
+					Out:   "file.txt",
+				}
+				require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte(content), files.RwRR))
+				require.NoError(t, os.WriteFile(filepath.Join(srcdir, "secret"), []byte("secret"), files.RwRR))
+				require.NoError(t, os.Symlink(filepath.Join(srcdir, "secret"), filepath.Join(destdir, "link")))
+
+				// Act
+				err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{})
+
+				// Assert
+				var escape *engine.EscapeError
+				assert.ErrorAs(t, err, &escape)
+				assert.NoFileExists(t, filepath.Join(destdir, template.Out))
+			})
+		}
+	})
+
+	t.Run("success_fs_funcs", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Globs: []string{"file.txt" + engine.TmplExtension},
+			Out:   "file.txt",
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, template.Globs[0]), []byte(`{{ readFile ".env.example" }}
+{{ fileExists "Dockerfile" }} {{ fileExists "Containerfile" }}
+{{ glob "migrations/*.sql" | join "," }}
+{{ listDir "./migrations/" | join "," }}`), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, ".env.example"), []byte("KEY=value"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, "Dockerfile"), []byte("FROM scratch"), files.RwRR))
+		require.NoError(t, os.Mkdir(filepath.Join(destdir, "migrations"), files.RwxRxRxRx))
+		for _, name := range []string{"1.sql", "2.sql", "README.md"} {
+			require.NoError(t, os.WriteFile(filepath.Join(destdir, "migrations", name), nil, files.RwRR))
+		}
+
+		var events []engine.Event
+		engine.Configure(engine.WithEventSink(func(event engine.Event) {
+			if event.Kind == engine.EventFileRead {
+				events = append(events, event)
+			}
+		}))
+		t.Cleanup(func() { engine.Configure() })
+
+		// Act
+		err := engine.ApplyTemplate(os.DirFS(srcdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "KEY=value\ntrue false\nmigrations/1.sql,migrations/2.sql\n1.sql,2.sql,README.md", string(content))
+		assert.Equal(t, []engine.Event{
+			{Kind: engine.EventFileRead, Index: -1, Path: filepath.Join(destdir, ".env.example"), Reason: "readFile"},
+			{Kind: engine.EventFileRead, Index: -1, Path: filepath.Join(destdir, "Dockerfile"), Reason: "fileExists"},
+			{Kind: engine.EventFileRead, Index: -1, Path: filepath.Join(destdir, "Containerfile"), Reason: "fileExists"},
+			{Kind: engine.EventFileRead, Index: -1, Path: filepath.Join(destdir, "migrations", "*.sql"), Reason: "glob"},
+			{Kind: engine.EventFileRead, Index: -1, Path: filepath.Join(destdir, "migrations"), Reason: "listDir"},
+		}, events)
+	})
 }
 
 type testmodule struct {
@@ -210,6 +525,30 @@ func TestGeneratorModules(t *testing.T) {
 		assert.Equal(t, ".", string(content))
 	})
 
+	t.Run("success_module_fs_funcs", func(t *testing.T) {
+		// Arrange
+		srcdir := t.TempDir()
+		destdir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, "file.txt"+engine.TmplExtension), []byte(`{{ readFile "VERSION" }}`), files.RwRR))
+		require.NoError(t, os.MkdirAll(filepath.Join(destdir, "api"), files.RwxRxRxRx))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, "VERSION"), []byte("root"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, "api", "VERSION"), []byte("api"), files.RwRR))
+
+		generator := engine.GeneratorModules(os.DirFS(srcdir), modules,
+			[]engine.Template[testmodule]{
+				{Globs: []string{"file.txt" + engine.TmplExtension}, Out: "file.txt"},
+			})
+
+		// Act
+		err := generator(ctx, destdir, []testmodule{{directory: "api"}})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, "api", "file.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "api", string(content))
+	})
+
 	t.Run("success_multiple_modules", func(t *testing.T) {
 		// Arrange
 		srcdir := t.TempDir()
@@ -366,6 +705,71 @@ index 332d5ce..39af8aa 100644
 		assert.Equal(t, "value", string(content))
 	})
 
+	t.Run("success_include", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Out:     "file.txt",
+			Patches: []string{"file.patch"},
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Patches[0]), []byte(`{{ define "line" }}+{{ .Str }}{{ end }}
+diff --git a/file.txt b/file.txt
+index 332d5ce..39af8aa 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,0 +1,1 @@
+{{ include "line" . }}`), files.RwRR))
+
+		// Act
+		err := engine.ApplyPatches(os.DirFS(destdir), destdir, template, testconfig{Str: "value"})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "value", string(content))
+	})
+
+	t.Run("success_template_funcs_data", func(t *testing.T) {
+		cases := map[string]struct {
+			data     any
+			expected string
+		}{
+			"config": {data: testconfig{Str: "value"}, expected: "value-value"},
+			"raw":    {data: map[string]string{"Name": "raw"}, expected: "raw-"},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				// Arrange
+				destdir := t.TempDir()
+				template := engine.Template[testconfig]{
+					Data: func(config testconfig) any { return map[string]string{"Name": config.Str} },
+					FuncMap: func(config testconfig) template.FuncMap {
+						return template.FuncMap{"str": func() string { return config.Str }}
+					},
+					Out:     "file.txt",
+					Patches: []string{"file.patch"},
+				}
+				require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Patches[0]), []byte(`
+diff --git a/file.txt b/file.txt
+index 332d5ce..39af8aa 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,0 +1,1 @@
++{{ .Name }}-{{ str }}`), files.RwRR))
+
+				// Act
+				err := engine.ApplyPatches(os.DirFS(destdir), destdir, template, tc.data)
+
+				// Assert
+				require.NoError(t, err)
+				content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, string(content))
+			})
+		}
+	})
+
 	t.Run("success_update_shorter", func(t *testing.T) {
 		// Arrange
 		destdir := t.TempDir()
@@ -423,6 +827,64 @@ index 332d5ce..39af8aa 100644
 		require.NoError(t, err)
 		assert.Equal(t, "some replaced value in non empty file", string(content))
 	})
+
+	t.Run("success_update_crlf", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Out:     "file.bat",
+			Patches: []string{"file.patch"},
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("\xEF\xBB\xBF@echo off\r\necho one\r\n"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Patches[0]), []byte(`
+diff --git a/file.bat b/file.bat
+index 332d5ce..39af8aa 100644
+--- a/file.bat
++++ b/file.bat
+@@ -1,2 +1,2 @@
+ @echo off
+-echo one
++echo two
+`), files.RwRR))
+
+		// Act
+		err := engine.ApplyPatches(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "\xEF\xBB\xBF@echo off\r\necho two\r\n", string(content))
+	})
+
+	t.Run("success_update_format", func(t *testing.T) {
+		// Arrange
+		destdir := t.TempDir()
+		template := engine.Template[testconfig]{
+			Format:  engine.Format{FinalNewline: engine.FinalNewlineRemove, LineEnding: engine.LineEndingCRLF},
+			Out:     "file.txt",
+			Patches: []string{"file.patch"},
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Out), []byte("one\n"), files.RwRR))
+		require.NoError(t, os.WriteFile(filepath.Join(destdir, template.Patches[0]), []byte(`
+diff --git a/file.txt b/file.txt
+index 332d5ce..39af8aa 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1 +1,2 @@
+ one
++two
+`), files.RwRR))
+
+		// Act
+		err := engine.ApplyPatches(os.DirFS(destdir), destdir, template, testconfig{})
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(destdir, template.Out))
+		require.NoError(t, err)
+		assert.Equal(t, "one\r\ntwo", string(content))
+	})
 }
 
 func TestExecuteTemplate(t *testing.T) {
@@ -568,6 +1030,58 @@ func TestExecuteTemplate(t *testing.T) {
 		assert.Equal(t, files.RwxRxRxRx&^files.Umask(), info.Mode())
 	})
 
+	t.Run("success_notice", func(t *testing.T) {
+		// Arrange
+		tmp := t.TempDir()
+		dest := filepath.Join(tmp, "script.sh")
+
+		tmpl, err := template.New("template.sh").Parse("#!/bin/sh\necho {{ .name }}\n")
+		require.NoError(t, err)
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, map[string]string{"name": "hi"}, dest, engine.PolicyRemove, 0, engine.ExecuteNotice("kickr"))
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "#!/bin/sh\n# Code generated by kickr; DO NOT EDIT.\necho hi\n", string(content))
+	})
+
+	t.Run("success_skip_empty_notice", func(t *testing.T) {
+		// Arrange
+		dest := filepath.Join(t.TempDir(), "file.yml")
+
+		tmpl, err := template.New("template.yml").Parse("")
+		require.NoError(t, err)
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0, engine.ExecuteNotice("kickr"))
+
+		// Assert
+		require.NoError(t, err)
+		assert.NoFileExists(t, dest)
+	})
+
+	t.Run("success_format", func(t *testing.T) {
+		// Arrange
+		dest := filepath.Join(t.TempDir(), "script.bat")
+
+		tmpl, err := template.New("template.bat").Parse("@echo off\necho hi\n")
+		require.NoError(t, err)
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0,
+			engine.ExecuteNotice("kickr"),
+			engine.ExecuteFormat(engine.Format{BOM: true, LineEnding: engine.LineEndingCRLF}))
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "\xEF\xBB\xBF@REM Code generated by kickr; DO NOT EDIT.\r\n@echo off\r\necho hi\r\n", string(content))
+	})
+
 	t.Run("success_skip_empty", func(t *testing.T) {
 		// Arrange
 		tmp := t.TempDir()
@@ -591,6 +1105,37 @@ func TestExecuteTemplate(t *testing.T) {
 		assert.NoFileExists(t, dest)
 	})
 
+	t.Run("success_skip_empty_yaml", func(t *testing.T) {
+		// Arrange
+		dest := filepath.Join(t.TempDir(), "file.yml")
+
+		tmpl, err := template.New("template.yml").Parse("---\n# nothing to see here\n")
+		require.NoError(t, err)
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NoFileExists(t, dest)
+	})
+
+	t.Run("success_empty_func", func(t *testing.T) {
+		// Arrange
+		dest := filepath.Join(t.TempDir(), "file.yml")
+
+		tmpl, err := template.New("template.yml").Parse("---\n")
+		require.NoError(t, err)
+
+		// Act
+		err = engine.ExecuteTemplate(tmpl, nil, dest, engine.PolicyRemove, 0,
+			engine.ExecuteEmptyFunc(func([]byte) bool { return false }))
+
+		// Assert
+		require.NoError(t, err)
+		assert.FileExists(t, dest)
+	})
+
 	t.Run("success_keep_empty", func(t *testing.T) {
 		// Arrange
 		tmp := t.TempDir()
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"text/template"
 )
 
 // Parser is the function to parse a specific part of target repository.
@@ -40,12 +41,44 @@ type Template[T any] struct {
 	// Delimiters is the pair of delimiters used to parse template file(s).
 	Delimiters
 
+	// Data function is run (if not nil) to compute the value given to template files and patches execution
+	// from the configuration, instead of the configuration itself.
+	//
+	// It allows templates shared across projects to rely on a stable and smaller data model
+	// than each project configuration.
+	Data func(config T) any
+
+	// EmptyFunc is the function (if not nil) detecting whether the generated file is empty,
+	// overriding the one associated to Out file type (see EmptyFuncFor).
+	EmptyFunc EmptyFunc
+
 	// EmptyPolicy is the policy to apply when the generated file is empty.
 	EmptyPolicy EmptyPolicy
 
+	// Format is the output format of the generated file (line endings, byte order mark and final newline).
+	//
+	// It's applied both on the generated file and after each patch application.
+	// Patches are always applied on the normalized content (LF line endings without byte order mark),
+	// as such they must be written with LF line endings.
+	Format Format
+
+	// FuncMap function is run (if not nil) to compute additional functions available in template files and patches,
+	// possibly closing over the configuration.
+	//
+	// Its functions are only available to the current template
+	// and take precedence over default ones (see FuncMap, including the bound "include", "tpl" and filesystem ones)
+	// and global ones (see WithFuncMap).
+	FuncMap func(config T) template.FuncMap
+
 	// GeneratePolicy is the generation policy of the current file.
 	GeneratePolicy GeneratePolicy
 
+	// GeneratePolicyFunc function is run (if not nil) to compute the generation policy of the current file
+	// from the configuration (e.g. depending on detected languages).
+	//
+	// It takes precedence over GeneratePolicy.
+	GeneratePolicyFunc func(config T) GeneratePolicy
+
 	// Globs is the slice of globs or specific files to parse during go templating.
 	//
 	// It allows the current file to be split into multiple template files
@@ -65,6 +98,18 @@ type Template[T any] struct {
 	// restrictive than requested depending on the running user's umask.
 	Mode os.FileMode
 
+	// Notice is the generator name to inject as generated notice ("Code generated by <Notice>; DO NOT EDIT.")
+	// at the top of the generated file, with the comment syntax associated to Out (see CommentFor and InjectNotice).
+	//
+	// The notice is kept after shebang lines, XML declarations, YAML directives and document start markers,
+	// Dockerfile parser directives and Markdown front matter.
+	// It's not injected when the template already writes a notice itself
+	// or when Out doesn't support comments (e.g. JSON), in which case "notice" template function can be used instead.
+	//
+	// When provided, an existing Out whose generated notice names another generator (see GeneratedBy) isn't overwritten,
+	// unless GeneratePolicy is PolicyAlways or generation is forced.
+	Notice string
+
 	// Out is the output file path.
 	//
 	// It must be the full path to destination directory with the filename.
@@ -95,7 +140,19 @@ type Template[T any] struct {
 	Patches []string
 
 	// Remove function is run (if not nil) to verify whether the out file should be removed or not.
+	//
+	// When the out file is a symbolic link, the link itself is removed, not its target.
 	Remove func(config T) bool
+
+	// Symlink is the target path (slash-separated and relative to the generation destination directory)
+	// of a relative symbolic link to create at Out, instead of generating Globs (Patches aren't applied either).
+	//
+	// With GeneratorModules, it stays relative to the repository root (the destination directory given to Generate),
+	// e.g. with Symlink ".golangci.yml" and Out ".golangci.yml", the module "apps/api" receives a link to "../../.golangci.yml".
+	//
+	// An existing symbolic link is updated to the right target, except with PolicyNever or PolicyCreateOnce,
+	// and an existing file is replaced according to GeneratePolicy (see ShouldGenerate).
+	Symlink string
 }
 
 const (
@@ -0,0 +1,192 @@
+package testutils
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+
+	engine "github.com/kickr-dev/engine/pkg"
+)
+
+var (
+	// KeyEnter is the key press submitting a field.
+	KeyEnter = tea.KeyPressMsg{Code: tea.KeyEnter}
+
+	// KeyTab is the key press moving to the next field.
+	KeyTab = tea.KeyPressMsg{Code: tea.KeyTab}
+
+	// KeyUp is the key press moving upwards in a select or multiselect.
+	KeyUp = tea.KeyPressMsg{Code: tea.KeyUp}
+
+	// KeyDown is the key press moving downwards in a select or multiselect.
+	KeyDown = tea.KeyPressMsg{Code: tea.KeyDown}
+
+	// KeyLeft is the key press moving to the left in a confirm.
+	KeyLeft = tea.KeyPressMsg{Code: tea.KeyLeft}
+
+	// KeyRight is the key press moving to the right in a confirm.
+	KeyRight = tea.KeyPressMsg{Code: tea.KeyRight}
+
+	// KeySpace is the key press marking or unmarking an item in a multiselect.
+	KeySpace = tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}
+
+	// KeyBackspace is the key press removing the previous character of an input.
+	KeyBackspace = tea.KeyPressMsg{Code: tea.KeyBackspace}
+)
+
+// FormScript is the scripted input of one form run.
+type FormScript []tea.Msg
+
+// Script returns the FormScript of input steps.
+//
+// A string step is typed as is (one key press per character) while any other step is sent as is,
+// e.g. Script("value", KeyEnter) fills an input and submits it.
+func Script(steps ...tea.Msg) FormScript {
+	script := make(FormScript, 0, len(steps))
+	for _, step := range steps {
+		text, ok := step.(string)
+		if !ok {
+			script = append(script, step)
+			continue
+		}
+		for _, r := range text {
+			script = append(script, tea.KeyPressMsg{Code: r, Text: string(r)})
+		}
+	}
+	return script
+}
+
+// Initialize runs engine.Initialize with a headless bubbletea program (no terminal is needed, e.g. in go test),
+// each form run receiving the messages of its script in order (engine.Initialize may run more than one form,
+// e.g. with engine.WithReview).
+//
+// It runs inside a synctest bubble (see synctest.Test), each scripted message being sent once all goroutines
+// of the bubble are blocked, that is once the previous message and the commands it returned were processed.
+//
+// It returns the resulting configuration, the validation errors shown during the runs and engine.Initialize error.
+// A form still running once its script is fully sent (or without any script) is aborted,
+// engine.Initialize then returns huh.ErrUserAborted.
+//
+// engine.WithTeaOptions and engine.WithFormRunner must not be given since they're overridden.
+func Initialize[T any](t *testing.T, scripts []FormScript, opts ...engine.InitializeOption[T]) (config T, errs []error, err error) {
+	t.Helper()
+	synctest.Test(t, func(t *testing.T) {
+		d := &driver{programs: make(chan *tea.Program)}
+		opts = append(opts,
+			engine.WithFormRunner[T](d.runner),
+			engine.WithTeaOptions[T](
+				tea.WithContext(t.Context()),
+				tea.WithInput(nil),
+				tea.WithOutput(io.Discard),
+				tea.WithoutRenderer(),
+				tea.WithoutSignalHandler(),
+				d.program,
+				tea.WithFilter(d.filter),
+			))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			d.drive(scripts)
+		}()
+
+		config, err = engine.Initialize(t.Context(), opts...)
+		close(d.programs)
+		<-done
+		errs = d.errs
+
+		// commands left running (e.g. cursor blinks) wait for timers, let them fire since time is fake inside the bubble
+		time.Sleep(time.Minute)
+	})
+	return config, errs, err
+}
+
+// driver sends the scripted messages to each program run by engine.Initialize.
+type driver struct {
+	current  *tea.Program
+	errs     []error
+	form     *huh.Form
+	programs chan *tea.Program
+	shown    []string
+	started  bool
+}
+
+// runner is the engine.WithFormRunner function keeping track of the current form.
+func (d *driver) runner(ctx context.Context, form *huh.Form) error {
+	d.form = form
+	return form.RunWithContext(ctx)
+}
+
+// program is the tea.ProgramOption keeping track of the current program.
+func (d *driver) program(p *tea.Program) {
+	d.current = p
+	d.started = false
+}
+
+// filter hands the current program over to drive once its event loop runs
+// and keeps track of the validation errors shown.
+func (d *driver) filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	if !d.started {
+		d.started = true
+		d.programs <- d.current
+	}
+
+	// errors are kept as long as they're shown, only new ones are added
+	shown := make([]string, 0, len(d.shown))
+	for _, err := range d.form.Errors() {
+		if !slices.Contains(d.shown, err.Error()) {
+			d.errs = append(d.errs, err)
+		}
+		shown = append(shown, err.Error())
+	}
+	d.shown = shown
+	return msg
+}
+
+// drive sends all messages of each input script to the matching program (in order),
+// and aborts the program in case it's still running afterwards.
+func (d *driver) drive(scripts []FormScript) {
+	for run := 0; ; run++ {
+		p, ok := <-d.programs
+		if !ok {
+			return
+		}
+
+		done := make(chan struct{})
+		go func() {
+			p.Wait()
+			close(done)
+		}()
+
+		var script FormScript
+		if run < len(scripts) {
+			script = scripts[run]
+		}
+		for _, msg := range script {
+			if synctest.Wait(); finished(done) {
+				break
+			}
+			p.Send(msg)
+		}
+		if synctest.Wait(); !finished(done) {
+			p.Send(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl})
+		}
+		<-done
+	}
+}
+
+// finished returns true when input channel is closed.
+func finished(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}